@@ -0,0 +1,55 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PruneAnnotation can be set on a child to control pruning.
+	// Setting it to PruneDisabled keeps the child alive after it leaves the desired set.
+	PruneAnnotation = "reconcileprune.io/prune"
+
+	// PruneDisabled is the PruneAnnotation value that disables pruning.
+	PruneDisabled = "disabled"
+
+	// argoSyncOptionsAnnotation carries Argo CD sync options such as "Prune=false".
+	argoSyncOptionsAnnotation = "argocd.argoproj.io/sync-options"
+
+	// kappDeleteStrategyAnnotation carries the kapp delete strategy, "orphan" keeps the object.
+	kappDeleteStrategyAnnotation = "kapp.k14s.io/delete-strategy"
+)
+
+// pruneDisabled reports whether the live object opts out of pruning, either
+// through PruneAnnotation or through the well-known Argo CD and kapp annotations.
+func pruneDisabled(obj client.Object) bool {
+	annotations := obj.GetAnnotations()
+
+	if annotations[PruneAnnotation] == PruneDisabled {
+		return true
+	}
+	if annotations[kappDeleteStrategyAnnotation] == "orphan" {
+		return true
+	}
+	for _, option := range strings.Split(annotations[argoSyncOptionsAnnotation], ",") {
+		if strings.TrimSpace(option) == "Prune=false" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPruneDisabled(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{name: "no annotations", want: false},
+		{name: "reconcileprune disabled", annotations: map[string]string{PruneAnnotation: PruneDisabled}, want: true},
+		{name: "reconcileprune other value", annotations: map[string]string{PruneAnnotation: "enabled"}, want: false},
+		{name: "argo prune false", annotations: map[string]string{argoSyncOptionsAnnotation: "ServerSideApply=true, Prune=false"}, want: true},
+		{name: "argo other options", annotations: map[string]string{argoSyncOptionsAnnotation: "ServerSideApply=true"}, want: false},
+		{name: "kapp orphan", annotations: map[string]string{kappDeleteStrategyAnnotation: "orphan"}, want: true},
+		{name: "kapp default", annotations: map[string]string{kappDeleteStrategyAnnotation: ""}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := pruneDisabled(obj); got != tt.want {
+				t.Errorf("pruneDisabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruner_SkipsAnnotatedChild(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	kept := newTestDeployment("kept")
+	kept.Annotations = map[string]string{PruneAnnotation: PruneDisabled}
+	pruned := newTestDeployment("pruned")
+
+	pruner, err := pruneAfterRemoval(t, cl, owner, []client.Object{kept, pruned}, WithScheme(scheme))
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	skipped := pruner.Skipped()
+	if len(skipped) != 1 || skipped[0].ObjectReference.Name != kept.Name || skipped[0].Reason != SkipReasonAnnotation {
+		t.Errorf("Expected %s to be skipped by annotation, got %+v", kept.Name, skipped)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(kept), &appsv1.Deployment{}); err != nil {
+		t.Errorf("Annotated deployment should still exist: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(pruned), &appsv1.Deployment{}); err == nil {
+		t.Errorf("Expected %s to be deleted", pruned.Name)
+	}
+	if len(owner.Status.Inventory.Children) != 0 {
+		t.Errorf("Expected skipped child to be dropped from the inventory, got %+v", owner.Status.Inventory.Children)
+	}
+}
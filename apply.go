@@ -0,0 +1,155 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ApplyOption configures a single Apply call.
+type ApplyOption func(*applyOptions)
+
+// applyOptions holds the settings of an Apply call.
+type applyOptions struct {
+	controllerReference bool
+}
+
+// WithControllerReference makes Apply set the owner as the controller
+// reference of the object before applying it.
+//
+// Example:
+//
+//	err := pruner.Apply(ctx, deployment, reconcileprune.WithControllerReference())
+func WithControllerReference() ApplyOption {
+	return func(o *applyOptions) {
+		o.controllerReference = true
+	}
+}
+
+// Apply server-side applies obj with the field manager set by WithFieldOwner,
+// forcing ownership of conflicting fields, then marks it as reconciled.
+// obj is updated with the object returned by the API server, including its UID.
+// Its resourceVersion is not sent, so obj may have been read from the cluster.
+//
+// When the apply fails, the failure is recorded: Prune keeps the child, even
+// if it belongs to a previous generation, and a pruner created with
+// NewInventoryPruner does not complete the generation, so the next reconcile
+// retries with the same pruning decision. A later successful Apply or
+// MarkReconciled of the same child clears the failure.
+//
+// Apply is safe for concurrent use. It returns ErrPruneStarted, without
+// applying obj, once Prune has started. Prune, PruneAll and Teardown wait for
+// the Apply calls already in flight, so a child applied concurrently with them
+// is tracked and never pruned.
+//
+// Example:
+//
+//	pruner := reconcileprune.NewInventoryPruner(r.Client, &myCR, &myCR.Status.Inventory,
+//	    reconcileprune.WithFieldOwner("my-controller"),
+//	)
+//	if err := pruner.Apply(ctx, deployment, reconcileprune.WithControllerReference()); err != nil {
+//	    return ctrl.Result{}, err
+//	}
+func (p *Pruner) Apply(ctx context.Context, obj client.Object, opts ...ApplyOption) error {
+	options := applyOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	gvk, err := p.gvkFor(obj)
+	if err != nil {
+		return fmt.Errorf("failed to generate reference for object: %w", err)
+	}
+
+	// A child created after Prune started could not be tracked; an admitted
+	// apply is waited for by Prune, so its child is marked even if Prune
+	// starts in the meantime
+	p.mu.Lock()
+	if p.sealed {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: %s %s cannot be applied", ErrPruneStarted, gvk.Kind, client.ObjectKeyFromObject(obj))
+	}
+	p.applies.Add(1)
+	p.mu.Unlock()
+	defer p.applies.Done()
+
+	if err := p.apply(ctx, obj, gvk, options); err != nil {
+		p.recordApplyError(ctx, gvk, obj, err)
+		return err
+	}
+	if obj.GetUID() == "" {
+		return fmt.Errorf("applied %s %s has no UID", gvk.Kind, client.ObjectKeyFromObject(obj))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.markDesired(corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+	})
+	return nil
+}
+
+// apply sends the server-side apply request for obj.
+func (p *Pruner) apply(ctx context.Context, obj client.Object, gvk schema.GroupVersionKind, options applyOptions) error {
+	if p.fieldOwner == "" {
+		return errors.New("a field owner is required to apply objects, see WithFieldOwner")
+	}
+
+	if options.controllerReference {
+		if err := controllerutil.SetControllerReference(p.owner, obj, p.runtimeScheme()); err != nil {
+			return fmt.Errorf("failed to set controller reference on %s %s: %w",
+				gvk.Kind, client.ObjectKeyFromObject(obj), err)
+		}
+	}
+
+	// The apply body must carry the type and must not carry managed fields.
+	// A resourceVersion would make the apply an optimistic update, failing with
+	// a conflict whenever obj was read before the live object last changed.
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	if err := p.client.Patch(ctx, obj, client.Apply, client.ForceOwnership, client.FieldOwner(p.fieldOwner)); err != nil {
+		return fmt.Errorf("failed to apply %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+	}
+	return nil
+}
+
+// recordApplyError remembers that obj could not be applied in this session.
+func (p *Pruner) recordApplyError(ctx context.Context, gvk schema.GroupVersionKind, obj client.Object, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ref := corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+	p.applyErrors[IdentityFromReference(ref)] = err
+
+	p.logger(ctx).V(logLevelDebug).Info("Failed to apply child", append(childLogValues(ref), "error", err.Error())...)
+}
@@ -0,0 +1,300 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// applyCall records the options of a server-side apply request.
+type applyCall struct {
+	name         string
+	fieldManager string
+	force        bool
+}
+
+// newApplyClient returns a fake client emulating server-side apply, which the
+// fake client does not support, with a create or update. Applies of objects
+// named in failing are rejected, and so are applies carrying a stale
+// resourceVersion, like the API server does.
+func newApplyClient(calls *[]applyCall, failing map[string]bool) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(setupScheme()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if patch.Type() != types.ApplyPatchType {
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				options := (&client.PatchOptions{}).ApplyOptions(opts)
+				*calls = append(*calls, applyCall{
+					name:         obj.GetName(),
+					fieldManager: options.FieldManager,
+					force:        options.Force != nil && *options.Force,
+				})
+				if failing[obj.GetName()] {
+					return errors.New("apply rejected")
+				}
+
+				obj.SetUID(types.UID(obj.GetName() + "-applied-uid"))
+				err := c.Create(ctx, obj)
+				if apierrors.IsAlreadyExists(err) {
+					live := obj.DeepCopyObject().(client.Object)
+					if err := c.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+						return err
+					}
+					if rv := obj.GetResourceVersion(); rv != "" && rv != live.GetResourceVersion() {
+						return apierrors.NewConflict(appsv1.Resource("deployments"), obj.GetName(), errors.New("the object has been modified"))
+					}
+					obj.SetUID(live.GetUID())
+					obj.SetResourceVersion(live.GetResourceVersion())
+					return c.Update(ctx, obj)
+				}
+				return err
+			},
+		}).
+		Build()
+}
+
+func TestApply_MarksReconciled(t *testing.T) {
+	ctx := context.Background()
+	var calls []applyCall
+	cl := newApplyClient(&calls, nil)
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	deployment.TypeMeta = metav1.TypeMeta{}
+	deployment.UID = ""
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithFieldOwner("test-controller"))
+	if err := pruner.Apply(ctx, deployment, WithControllerReference()); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if len(calls) != 1 || calls[0].fieldManager != "test-controller" || !calls[0].force {
+		t.Errorf("Expected a forced apply with the field owner, got %+v", calls)
+	}
+
+	live := &appsv1.Deployment{}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(deployment), live); err != nil {
+		t.Fatalf("Failed to get deployment: %v", err)
+	}
+	if ref := metav1.GetControllerOf(live); ref == nil || ref.UID != owner.UID {
+		t.Errorf("Expected the owner as controller reference, got %+v", live.OwnerReferences)
+	}
+
+	children := owner.Status.Inventory.Children
+	if len(children) != 1 || children[0].ObjectReference.UID != "test-deployment-applied-uid" {
+		t.Errorf("Expected the applied child with the server UID in the inventory, got %+v", children)
+	}
+}
+
+func TestApply_PreviouslyReadObject(t *testing.T) {
+	ctx := context.Background()
+	var calls []applyCall
+	cl := newApplyClient(&calls, nil)
+
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithFieldOwner("test-controller"))
+	if err := pruner.Apply(ctx, newTestDeployment("test-deployment")); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// The controller reads the object, then someone else changes it
+	read := &appsv1.Deployment{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test-deployment"}, read); err != nil {
+		t.Fatalf("Failed to get deployment: %v", err)
+	}
+	changed := read.DeepCopy()
+	changed.Labels = map[string]string{"changed": "true"}
+	if err := cl.Update(ctx, changed); err != nil {
+		t.Fatalf("Failed to update deployment: %v", err)
+	}
+
+	owner.SetGeneration(2)
+	pruner = NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithFieldOwner("test-controller"))
+	if err := pruner.Apply(ctx, read); err != nil {
+		t.Fatalf("Expected the apply of a previously read object to succeed, got %v", err)
+	}
+}
+
+func TestApply_RequiresFieldOwner(t *testing.T) {
+	ctx := context.Background()
+	var calls []applyCall
+	cl := newApplyClient(&calls, nil)
+
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory)
+	if err := pruner.Apply(ctx, newTestDeployment("test-deployment")); err == nil {
+		t.Fatal("Expected Apply to fail without a field owner")
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected no apply request, got %+v", calls)
+	}
+	if len(owner.Status.Inventory.Children) != 0 {
+		t.Errorf("Expected nothing to be marked, got %+v", owner.Status.Inventory.Children)
+	}
+}
+
+func TestApply_FailureKeepsChild(t *testing.T) {
+	ctx := context.Background()
+	var calls []applyCall
+	failing := map[string]bool{}
+	cl := newApplyClient(&calls, failing)
+
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithFieldOwner("test-controller"))
+	for _, name := range []string{"failing", "applied", "stale"} {
+		if err := pruner.Apply(ctx, newTestDeployment(name)); err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Generation 2 drops "stale" but fails to apply "failing"
+	failing["failing"] = true
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithFieldOwner("test-controller"))
+	if err := pruner2.Apply(ctx, newTestDeployment("failing")); err == nil {
+		t.Fatal("Expected Apply to fail")
+	}
+	if err := pruner2.Apply(ctx, newTestDeployment("applied")); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	result, err := pruner2.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	outcomes := map[string]ChildResult{}
+	for _, child := range result.Children {
+		outcomes[child.ObjectReference.Name] = child
+	}
+	if got := outcomes["failing"]; got.Outcome != OutcomeKept || got.Reason != ReasonApplyFailed {
+		t.Errorf("Expected the failed child to be kept, got %+v", got)
+	}
+	if got := outcomes["stale"]; got.Outcome != OutcomeDeleted {
+		t.Errorf("Expected the stale child to be deleted, got %+v", got)
+	}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "failing"}, &appsv1.Deployment{}); err != nil {
+		t.Errorf("Expected the failed child to still exist: %v", err)
+	}
+	if owner.Status.Inventory.CompletedGeneration != 1 {
+		t.Errorf("Expected generation 2 to stay incomplete, got %d", owner.Status.Inventory.CompletedGeneration)
+	}
+
+	// The retry succeeds and completes the generation
+	delete(failing, "failing")
+	pruner3 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithFieldOwner("test-controller"))
+	for _, name := range []string{"failing", "applied"} {
+		if err := pruner3.Apply(ctx, newTestDeployment(name)); err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+	}
+	if _, err := pruner3.Prune(ctx); err != nil {
+		t.Fatalf("Third Prune failed: %v", err)
+	}
+	if owner.Status.Inventory.CompletedGeneration != 2 {
+		t.Errorf("Expected generation 2 to be completed, got %d", owner.Status.Inventory.CompletedGeneration)
+	}
+}
+
+func TestApply_InFlightDuringPrune(t *testing.T) {
+	ctx := context.Background()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	cl := fake.NewClientBuilder().
+		WithScheme(setupScheme()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if patch.Type() != types.ApplyPatchType {
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				close(started)
+				<-release
+				live := &appsv1.Deployment{}
+				if err := c.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+					return err
+				}
+				obj.SetUID(live.GetUID())
+				obj.SetResourceVersion(live.GetResourceVersion())
+				return c.Update(ctx, obj)
+			},
+		}).
+		Build()
+	deps := createDeployments(t, cl, "test-deployment")
+
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithFieldOwner("test-controller"))
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Generation 2 starts Prune while the apply of the child is in flight
+	owner.SetGeneration(2)
+	pruner = NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithFieldOwner("test-controller"))
+	applyErr := make(chan error)
+	go func() {
+		applyErr <- pruner.Apply(ctx, deps[0].DeepCopy())
+	}()
+	<-started
+
+	type pruneReturn struct {
+		result *PruneResult
+		err    error
+	}
+	pruned := make(chan pruneReturn)
+	go func() {
+		result, err := pruner.Prune(ctx)
+		pruned <- pruneReturn{result, err}
+	}()
+	select {
+	case <-pruned:
+		t.Fatal("Expected Prune to wait for the in-flight apply")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-applyErr; err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	got := <-pruned
+	if got.err != nil {
+		t.Fatalf("Second Prune failed: %v", got.err)
+	}
+
+	if got.result.Counts[OutcomeDeleted] != 0 {
+		t.Errorf("Expected the applied child to be kept, got %+v", got.result.Children)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(deps[0]), &appsv1.Deployment{}); err != nil {
+		t.Errorf("Expected the applied child to still exist: %v", err)
+	}
+	children := owner.Status.Inventory.Children
+	if len(children) != 1 || children[0].ObservedGeneration != 2 {
+		t.Errorf("Expected the applied child to be tracked at generation 2, got %+v", children)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EpochSource stands in for metadata.generation on owners that always report
+// a generation of 0, such as ConfigMaps, built-in types without a generation,
+// or aggregated API objects. It is only used when the owner's generation is 0.
+type EpochSource struct {
+	// fingerprint, when set, is stored in the inventory and compared instead
+	// of a generation
+	fingerprint func(owner client.Object) (string, error)
+	epoch       func(owner client.Object) (int64, error)
+}
+
+// EpochFromResourceVersion prunes whenever the owner's resourceVersion
+// changes. The resourceVersion is opaque, and not numeric on every API server,
+// so it is only compared for equality: it is stored as the inventory's
+// Fingerprint, combined with the one from WithFingerprint if any, and requires
+// an Inventory, see NewInventoryPruner.
+// The resourceVersion changes on every write to the owner, including writes
+// of an inventory kept in its status or annotations, so it best suits owners
+// whose inventory lives in a separate store, see NewConfigMapStore.
+func EpochFromResourceVersion() EpochSource {
+	return EpochSource{fingerprint: func(owner client.Object) (string, error) {
+		if owner.GetResourceVersion() == "" {
+			return "", errors.New("owner has no resourceVersion to use as an epoch, was it read from the API server?")
+		}
+		return owner.GetResourceVersion(), nil
+	}}
+}
+
+// EpochFromCounter uses a caller-supplied counter as the owner's generation,
+// for example a counter kept in an annotation. The counter must increase
+// whenever the desired set of children changes, and never decrease.
+func EpochFromCounter(counter func(owner client.Object) int64) EpochSource {
+	return EpochSource{epoch: func(owner client.Object) (int64, error) {
+		return counter(owner), nil
+	}}
+}
+
+// EpochFromSpecHash prunes whenever a hash of the owner's content, everything
+// but its metadata and status, changes. The hash is stored as the inventory's
+// Fingerprint, combined with the one from WithFingerprint if any, so it
+// requires an Inventory, see NewInventoryPruner.
+func EpochFromSpecHash() EpochSource {
+	return EpochSource{fingerprint: ownerSpecHash}
+}
+
+// isZero reports whether no epoch source was configured.
+func (s EpochSource) isZero() bool {
+	return s.fingerprint == nil && s.epoch == nil
+}
+
+// sessionGeneration returns the generation the session runs at: the owner's
+// generation or, when the owner has none, the epoch from the configured
+// source. With EpochFromSpecHash and EpochFromResourceVersion the generation
+// stays 0 and the owner's fingerprint is returned instead.
+func (p *Pruner) sessionGeneration() (generation int64, fingerprint string, err error) {
+	generation = p.owner.GetGeneration()
+	if generation != 0 || p.epochSource.isZero() {
+		return generation, "", nil
+	}
+	if p.epochSource.fingerprint != nil {
+		fingerprint, err = p.epochSource.fingerprint(p.owner)
+		return 0, fingerprint, err
+	}
+	generation, err = p.epochSource.epoch(p.owner)
+	return generation, "", err
+}
+
+// ownerSpecHash hashes the content of the owner outside of its metadata and status.
+func ownerSpecHash(owner client.Object) (string, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(owner)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert owner to hash its spec: %w", err)
+	}
+	for _, field := range []string{"apiVersion", "kind", "metadata", "status"} {
+		delete(content, field)
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode owner to hash its spec: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
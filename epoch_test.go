@@ -0,0 +1,180 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newConfigMapOwner returns a ConfigMap owner, which has no generation.
+func newConfigMapOwner() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-owner", Namespace: "default", UID: "test-uid"},
+		Data:       map[string]string{"replicas": "1"},
+	}
+}
+
+func TestEpoch_ResourceVersion(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "kept", "unmarked")
+
+	owner := newConfigMapOwner()
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	var inventory Inventory
+	opts := []Option{WithScheme(scheme), WithEpochSource(EpochFromResourceVersion())}
+	if _, err := markAndPrune(t, NewInventoryPruner(cl, owner, &inventory, opts...), deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if inventory.Fingerprint != owner.ResourceVersion || inventory.CompletedGeneration != 0 {
+		t.Fatalf("Expected the resourceVersion to be recorded as the fingerprint, got %+v", inventory)
+	}
+
+	// Same resourceVersion: nothing is pruned
+	result, err := markAndPrune(t, NewInventoryPruner(cl, owner, &inventory, opts...), deps[0])
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if result.Counts[OutcomeDeleted] != 0 {
+		t.Errorf("Expected nothing to be deleted, got %+v", result.Children)
+	}
+
+	// The owner changed: the unmarked child is pruned
+	owner.Data["replicas"] = "2"
+	if err := cl.Update(ctx, owner); err != nil {
+		t.Fatalf("Failed to update owner: %v", err)
+	}
+	result, err = markAndPrune(t, NewInventoryPruner(cl, owner, &inventory, opts...), deps[0])
+	if err != nil {
+		t.Fatalf("Third Prune failed: %v", err)
+	}
+	if result.Counts[OutcomeDeleted] != 1 {
+		t.Errorf("Expected the unmarked child to be deleted, got %+v", result.Children)
+	}
+}
+
+func TestEpoch_OpaqueResourceVersion(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "kept", "unmarked")
+
+	// Aggregated API servers may return non-numeric resourceVersions
+	owner := newConfigMapOwner()
+	owner.ResourceVersion = "etcd-a/7f3c"
+	var inventory Inventory
+	opts := []Option{WithScheme(scheme), WithEpochSource(EpochFromResourceVersion())}
+	if _, err := markAndPrune(t, NewInventoryPruner(cl, owner, &inventory, opts...), deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	owner.ResourceVersion = "etcd-b/0a12"
+	result, err := markAndPrune(t, NewInventoryPruner(cl, owner, &inventory, opts...), deps[0])
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if result.Counts[OutcomeDeleted] != 1 {
+		t.Errorf("Expected the unmarked child to be deleted, got %+v", result.Children)
+	}
+}
+
+func TestEpoch_InvalidResourceVersion(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "test-deployment")
+
+	var inventory Inventory
+	pruner := NewInventoryPruner(cl, newConfigMapOwner(), &inventory,
+		WithScheme(scheme), WithEpochSource(EpochFromResourceVersion()))
+	if _, err := markAndPrune(t, pruner, deps...); err == nil {
+		t.Error("Expected Prune to fail without a resourceVersion")
+	}
+}
+
+func TestEpoch_Counter(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "kept", "unmarked")
+
+	epoch := int64(1)
+	owner := newTestOwner(0)
+	opts := []Option{WithScheme(scheme), WithEpochSource(EpochFromCounter(func(client.Object) int64 { return epoch }))}
+
+	pruner := NewPruner(cl, owner, &owner.Status.Children, opts...)
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	epoch = 2
+	pruner2 := NewPruner(cl, owner, &owner.Status.Children, opts...)
+	result, err := markAndPrune(t, pruner2, deps[0])
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if result.Counts[OutcomeDeleted] != 1 {
+		t.Errorf("Expected the unmarked child to be deleted, got %+v", result.Children)
+	}
+	if children := owner.Status.Children; len(children) != 1 || children[0].ObservedGeneration != 2 {
+		t.Errorf("Expected the kept child at epoch 2, got %+v", children)
+	}
+}
+
+func TestEpoch_SpecHash(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "kept", "unmarked")
+
+	owner := newConfigMapOwner()
+	var inventory Inventory
+	opts := []Option{WithScheme(scheme), WithEpochSource(EpochFromSpecHash())}
+	if _, err := markAndPrune(t, NewInventoryPruner(cl, owner, &inventory, opts...), deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if inventory.Fingerprint == "" {
+		t.Fatal("Expected the spec hash to be stored as the fingerprint")
+	}
+
+	// Only metadata changed: nothing is pruned
+	owner.Labels = map[string]string{"changed": "true"}
+	result, err := markAndPrune(t, NewInventoryPruner(cl, owner, &inventory, opts...), deps[0])
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if got := result.Children[1]; got.Outcome != OutcomeKept || got.Reason != ReasonFingerprintUnchanged {
+		t.Errorf("Expected the unmarked child to be kept, got %+v", got)
+	}
+
+	// The content changed: the unmarked child is pruned
+	owner.Data["replicas"] = "2"
+	result, err = markAndPrune(t, NewInventoryPruner(cl, owner, &inventory, opts...), deps[0])
+	if err != nil {
+		t.Fatalf("Third Prune failed: %v", err)
+	}
+	if result.Counts[OutcomeDeleted] != 1 {
+		t.Errorf("Expected the unmarked child to be deleted, got %+v", result.Children)
+	}
+	if inventory.CompletedGeneration != 0 {
+		t.Errorf("Expected the generation to stay 0, got %d", inventory.CompletedGeneration)
+	}
+}
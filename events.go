@@ -0,0 +1,88 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Event reasons used by Prune when an event recorder is configured.
+// See WithEventRecorder.
+const (
+	// EventReasonPruned is a Normal event emitted for each pruned child.
+	EventReasonPruned = "Pruned"
+
+	// EventReasonPruneFailed is a Warning event emitted for each failed deletion.
+	EventReasonPruneFailed = "PruneFailed"
+
+	// EventReasonPruneSkipped is a Normal event emitted for each child left in place.
+	EventReasonPruneSkipped = "PruneSkipped"
+
+	// EventReasonPruneSummary is emitted once per Prune call that acted on at
+	// least one child. It is a Warning when some deletions failed.
+	EventReasonPruneSummary = "PruneSummary"
+
+	// EventReasonPruneThresholdExceeded is a Warning event emitted when
+	// PruneLimits refused a Prune call.
+	EventReasonPruneThresholdExceeded = "PruneThresholdExceeded"
+)
+
+// recordEvents emits events on the owner for the outcome of a Prune call.
+func (p *Pruner) recordEvents(result *PruneResult, pruneErr error) {
+	if p.recorder == nil {
+		return
+	}
+
+	// Every child is kept when a threshold trips, which would go unnoticed
+	var thresholdErr *PruneThresholdError
+	if errors.As(pruneErr, &thresholdErr) {
+		p.recorder.Eventf(p.owner, corev1.EventTypeWarning, EventReasonPruneThresholdExceeded,
+			"Refused to prune %d of %d children: %s", len(thresholdErr.Candidates), thresholdErr.Total, thresholdErr.Reason)
+	}
+
+	for _, child := range result.Children {
+		id := IdentityFromReference(child.ObjectReference)
+		switch child.Outcome {
+		case OutcomeDeleted:
+			p.recorder.Eventf(p.owner, corev1.EventTypeNormal, EventReasonPruned,
+				"Pruned %s: %s", id, child.Reason)
+		case OutcomeDryRun:
+			p.recorder.Eventf(p.owner, corev1.EventTypeNormal, EventReasonPruned,
+				"Would prune %s (dry-run): %s", id, child.Reason)
+		case OutcomeFailed:
+			p.recorder.Eventf(p.owner, corev1.EventTypeWarning, EventReasonPruneFailed,
+				"Failed to prune %s: %v", id, child.Error)
+		case OutcomeSkipped:
+			p.recorder.Eventf(p.owner, corev1.EventTypeNormal, EventReasonPruneSkipped,
+				"Skipped pruning %s: %s", id, child.Reason)
+		}
+	}
+
+	kept := result.Counts[OutcomeKept]
+	if kept == len(result.Children) {
+		return
+	}
+	eventType := corev1.EventTypeNormal
+	if result.Counts[OutcomeFailed] > 0 {
+		eventType = corev1.EventTypeWarning
+	}
+	p.recorder.Eventf(p.owner, eventType, EventReasonPruneSummary,
+		"Prune finished: %d deleted, %d already gone, %d deleting, %d failed, %d ignored, %d skipped, %d dry-run, %d kept",
+		result.Counts[OutcomeDeleted], result.Counts[OutcomeAlreadyGone], result.Counts[OutcomeDeleting],
+		result.Counts[OutcomeFailed], result.Counts[OutcomeIgnored], result.Counts[OutcomeSkipped],
+		result.Counts[OutcomeDryRun], kept)
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// drainEvents returns the events currently buffered in the recorder.
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestEventRecorder_PruneEvents(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				if obj.GetName() == "failed" {
+					return errors.New("delete failed")
+				}
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	skipped := newTestDeployment("skipped")
+	skipped.Annotations = map[string]string{PruneAnnotation: PruneDisabled}
+	objs := []client.Object{newTestDeployment("deleted"), newTestDeployment("failed"), skipped}
+
+	recorder := record.NewFakeRecorder(10)
+	_, err := pruneAfterRemoval(t, cl, newTestOwner(1), objs, WithScheme(scheme), WithEventRecorder(recorder))
+	if err == nil {
+		t.Fatal("Expected Prune to fail")
+	}
+
+	want := []string{
+		"Normal Pruned Pruned Deployment.apps default/deleted",
+		"Warning PruneFailed Failed to prune Deployment.apps default/failed: ",
+		"Normal PruneSkipped Skipped pruning Deployment.apps default/skipped: " + SkipReasonAnnotation,
+		"Warning PruneSummary Prune finished: 1 deleted",
+	}
+	events := drainEvents(recorder)
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %q", len(want), events)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(events[i], w) {
+			t.Errorf("Event %d: expected %q, got %q", i, w, events[i])
+		}
+	}
+}
+
+func TestEventRecorder_NoEventsWithoutActivity(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithEventRecorder(recorder))
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	if events := drainEvents(recorder); len(events) != 0 {
+		t.Errorf("Expected no events, got %q", events)
+	}
+}
+
+func TestEventRecorder_ThresholdExceeded(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	objs := []client.Object{newTestDeployment("test-deployment-1"), newTestDeployment("test-deployment-2")}
+	recorder := record.NewFakeRecorder(10)
+	_, err := pruneAfterRemoval(t, cl, newTestOwner(1), objs,
+		WithScheme(scheme),
+		WithEventRecorder(recorder),
+		WithPruneLimits(PruneLimits{MaxCount: 1, AllowEmptyDesiredSet: true}),
+	)
+	if !errors.Is(err, ErrPruneThresholdExceeded) {
+		t.Fatalf("Expected ErrPruneThresholdExceeded, got %v", err)
+	}
+
+	want := "Warning PruneThresholdExceeded Refused to prune 2 of 2 children: more than 1 children"
+	if events := drainEvents(recorder); len(events) != 1 || events[0] != want {
+		t.Errorf("Expected %q, got %q", want, events)
+	}
+}
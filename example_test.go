@@ -165,7 +165,7 @@ func ExamplePruner_MarkReconciled() {
 	// Update status to persist changes
 	_ = cl.Status().Update(context.Background(), owner)
 
-	fmt.Printf("Pruned: %d resources\n", len(result))
+	fmt.Printf("Pruned: %d resources\n", len(result.Pruned()))
 	fmt.Printf("Children tracked: %d\n", len(owner.Status.Children))
 
 	// Output:
@@ -245,10 +245,10 @@ func ExamplePruner_MarkReconciled_withDryRun() {
 		reconcileprune.WithDryRun(true),
 	)
 
-	pruned, _ := pruner2.Prune(context.Background())
+	result, _ := pruner2.Prune(context.Background())
 	_ = cl.Status().Update(context.Background(), owner)
 
-	fmt.Printf("Pruned (dry-run): %d resources\n", len(pruned))
+	fmt.Printf("Pruned (dry-run): %d resources\n", result.Counts[reconcileprune.OutcomeDryRun])
 
 	// Output:
 	// Pruned (dry-run): 1 resources
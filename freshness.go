@@ -0,0 +1,97 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrStaleOwner is returned by Prune and PruneAll when the owner the Pruner was
+// created with is behind the stored one, typically because the informer cache
+// has not caught up with the previous reconcile yet. Nothing is deleted:
+// return the error, or requeue, to reconcile again with a fresh owner.
+var ErrStaleOwner = errors.New("owner is stale")
+
+// checkFreshOwner reads the owner through the reader set with
+// WithFreshOwnerReader and returns an error matching ErrStaleOwner when its
+// generation or stored inventory differs from the ones the session started with.
+// Inventories kept in an InventoryStore are reloaded as well.
+func (p *Pruner) checkFreshOwner(ctx context.Context) error {
+	if p.freshOwnerReader == nil {
+		return nil
+	}
+
+	key := client.ObjectKeyFromObject(p.owner)
+	stored := p.owner.DeepCopyObject().(client.Object)
+	if err := p.freshOwnerReader.Get(ctx, key, stored); err != nil {
+		return fmt.Errorf("failed to read owner %s: %w", key, err)
+	}
+
+	if stored.GetGeneration() != p.owner.GetGeneration() {
+		return fmt.Errorf("%w: %s has generation %d, the pruner was created with generation %d",
+			ErrStaleOwner, key, stored.GetGeneration(), p.owner.GetGeneration())
+	}
+
+	inventory, err := p.freshInventory(ctx, stored)
+	if err != nil {
+		return fmt.Errorf("failed to read the stored inventory of %s: %w", key, err)
+	}
+	if !sameInventory(inventory, p.baseInventory) {
+		return fmt.Errorf("%w: the stored inventory of %s differs from the one the pruner was created with",
+			ErrStaleOwner, key)
+	}
+	return nil
+}
+
+// freshInventory returns the inventory as currently stored, given stored, the
+// owner read through the fresh reader. Inventories kept in a ConfigMap or a
+// Secret are read through the fresh reader too; custom stores are reloaded
+// with their Load method.
+func (p *Pruner) freshInventory(ctx context.Context, stored client.Object) (Inventory, error) {
+	var (
+		inventory *Inventory
+		err       error
+	)
+	switch store := p.store.(type) {
+	case nil, *statusStore:
+		fields, err := p.inventoryFields()
+		if err != nil {
+			return Inventory{}, err
+		}
+		return fields.get(stored), nil
+	case *annotationStore:
+		inventory, err = decodeInventory([]byte(stored.GetAnnotations()[store.key]))
+	case *objectStore:
+		inventory, err = store.load(ctx, p.freshOwnerReader)
+	default:
+		inventory, err = store.Load(ctx)
+	}
+	if err != nil {
+		return Inventory{}, err
+	}
+	return *inventory, nil
+}
+
+// sameInventory reports whether a stored inventory matches the one a session started with.
+func sameInventory(stored, base Inventory) bool {
+	return slices.Equal(compactChildren(stored.Children), base.Children) &&
+		stored.CompletedGeneration == base.CompletedGeneration &&
+		stored.Fingerprint == base.Fingerprint
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFreshOwner_Matches(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&TestCR{}).Build()
+	deps := createDeployments(t, cl, "test-deployment")
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithFreshOwnerReader(cl))
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("Expected Prune to succeed with a fresh owner: %v", err)
+	}
+}
+
+func TestFreshOwner_StaleInventory(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&TestCR{}).Build()
+	deps := createDeployments(t, cl, "kept", "stale")
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+	cached := owner.DeepCopy()
+
+	// The previous reconcile stored both children
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if err := pruner.Persist(ctx); err != nil {
+		t.Fatalf("Persist failed: %v", err)
+	}
+
+	// The cache still returns the owner as it was before
+	pruner2 := NewInventoryPruner(cl, cached, &cached.Status.Inventory, WithScheme(scheme), WithFreshOwnerReader(cl))
+	_, err := markAndPrune(t, pruner2, deps[0])
+	if !errors.Is(err, ErrStaleOwner) || !strings.Contains(err.Error(), "inventory") {
+		t.Fatalf("Expected ErrStaleOwner for the inventory, got %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(deps[1]), &appsv1.Deployment{}); err != nil {
+		t.Errorf("Expected nothing to be deleted: %v", err)
+	}
+}
+
+func TestFreshOwner_StaleGeneration(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&TestCR{}).Build()
+
+	if err := cl.Create(ctx, newTestOwner(2)); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	cached := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, cached, &cached.Status.Inventory, WithScheme(scheme), WithFreshOwnerReader(cl))
+	if _, err := pruner.PruneAll(ctx); !errors.Is(err, ErrStaleOwner) {
+		t.Errorf("Expected ErrStaleOwner, got %v", err)
+	}
+}
+
+func TestFreshOwner_StaleStoreInventory(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "kept", "stale")
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	// The previous reconcile stored both children in a ConfigMap
+	pruner, err := NewPrunerWithStore(ctx, cl, owner, NewConfigMapStore(cl, owner, ""), WithScheme(scheme))
+	if err != nil {
+		t.Fatalf("NewPrunerWithStore failed: %v", err)
+	}
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if err := pruner.Save(ctx); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// An up-to-date ConfigMap passes the check
+	fresh, err := NewPrunerWithStore(ctx, cl, owner, NewConfigMapStore(cl, owner, ""),
+		WithScheme(scheme), WithFreshOwnerReader(cl), WithDryRun(true))
+	if err != nil {
+		t.Fatalf("NewPrunerWithStore failed: %v", err)
+	}
+	if _, err := markAndPrune(t, fresh, deps...); err != nil {
+		t.Fatalf("Expected Prune to succeed with a fresh inventory: %v", err)
+	}
+
+	// The cache has not seen the ConfigMap yet
+	cache := fake.NewClientBuilder().WithScheme(scheme).WithObjects(owner.DeepCopy()).Build()
+	pruner2, err := NewPrunerWithStore(ctx, cache, owner, NewConfigMapStore(cache, owner, ""),
+		WithScheme(scheme), WithFreshOwnerReader(cl))
+	if err != nil {
+		t.Fatalf("NewPrunerWithStore failed: %v", err)
+	}
+	if _, err := markAndPrune(t, pruner2, deps[0]); !errors.Is(err, ErrStaleOwner) {
+		t.Fatalf("Expected ErrStaleOwner for the ConfigMap inventory, got %v", err)
+	}
+}
+
+func TestFreshOwner_InventoryOutsideOwner(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	// The inventory is not a field of the owner: it cannot be compared
+	var inventory Inventory
+	pruner := NewInventoryPruner(cl, owner, &inventory, WithScheme(scheme), WithFreshOwnerReader(cl))
+	_, err := pruner.Prune(ctx)
+	if err == nil || errors.Is(err, ErrStaleOwner) {
+		t.Errorf("Expected an error about the inventory field, got %v", err)
+	}
+}
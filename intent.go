@@ -0,0 +1,93 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RecordIntent records, before a child is applied, that the owner is about to
+// create it, and persists the inventory right away with Persist. If the
+// controller crashes between the apply and the persistence of MarkReconciled,
+// the child is still tracked and cannot leak.
+//
+// The child is added in the Pending state. MarkReconciled confirms it. A
+// pending child recorded by an earlier session that is not marked becomes a
+// prune candidate once Prune runs for a new generation. Pending children
+// recorded in the current session are kept until they are confirmed.
+//
+// A pending child has no UID to guard its deletion: combine RecordIntent with
+// WithOwnerReferenceCheck or WithTrackingLabel to make sure a pending child is
+// only deleted if it was created by the owner.
+//
+// A pending child left by an earlier session is recorded again for the current
+// generation. Other children already in the inventory are left unchanged and
+// nothing is persisted.
+//
+// Example:
+//
+//	gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+//	if err := pruner.RecordIntent(ctx, gvk, client.ObjectKeyFromObject(deployment)); err != nil {
+//	    return ctrl.Result{}, err
+//	}
+//	if err := pruner.Apply(ctx, deployment); err != nil {
+//	    return ctrl.Result{}, err
+//	}
+func (p *Pruner) RecordIntent(ctx context.Context, gvk schema.GroupVersionKind, key client.ObjectKey) error {
+	if gvk.Kind == "" || key.Name == "" {
+		return fmt.Errorf("a kind and a name are required to record the intent to apply %s %s", gvk, key)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.sealed {
+		return fmt.Errorf("%w: the intent to apply %s %s cannot be recorded", ErrPruneStarted, gvk.Kind, key)
+	}
+
+	ref := corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  key.Namespace,
+		Name:       key.Name,
+	}
+	id := IdentityFromReference(ref)
+	switch i := p.statusChildren.Index(id); {
+	case i < 0:
+		*p.statusChildren = append(*p.statusChildren, ManagedChild{
+			ObjectReference:    ref,
+			ObservedGeneration: p.generation,
+			State:              ChildStatePending,
+		})
+		p.added[id] = struct{}{}
+	case (*p.statusChildren)[i].State == ChildStatePending:
+		// An intent left by a crashed session is recorded again for this one
+		(*p.statusChildren)[i].ObservedGeneration = p.generation
+	default:
+		return nil
+	}
+	p.intents[id] = struct{}{}
+	p.logger(ctx).V(logLevelDebug).Info("Recorded intent to apply child", childLogValues(ref)...)
+
+	if err := p.persist(ctx); err != nil {
+		return fmt.Errorf("failed to record intent: %w", err)
+	}
+	return nil
+}
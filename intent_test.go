@@ -0,0 +1,191 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var deploymentGVK = appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+// getStoredOwner reads the owner as a restarted controller would.
+func getStoredOwner(t *testing.T, cl client.Client) *TestCR {
+	t.Helper()
+	owner := &TestCR{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "test-owner"}, owner); err != nil {
+		t.Fatalf("Failed to get owner: %v", err)
+	}
+	return owner
+}
+
+func TestRecordIntent_PersistsPendingChild(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&TestCR{}).Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	deployment := newTestDeployment("test-deployment")
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.RecordIntent(ctx, deploymentGVK, client.ObjectKeyFromObject(deployment)); err != nil {
+		t.Fatalf("RecordIntent failed: %v", err)
+	}
+
+	children := getStoredOwner(t, cl).Status.Inventory.Children
+	if len(children) != 1 || children[0].State != ChildStatePending || children[0].ObjectReference.UID != "" {
+		t.Fatalf("Expected a persisted pending child, got %+v", children)
+	}
+
+	// The apply confirms the intent
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+	if _, err := markAndPrune(t, pruner, deployment); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if err := pruner.Persist(ctx); err != nil {
+		t.Fatalf("Persist failed: %v", err)
+	}
+
+	children = getStoredOwner(t, cl).Status.Inventory.Children
+	if len(children) != 1 || children[0].State != "" || children[0].ObjectReference.UID != deployment.UID {
+		t.Errorf("Expected the confirmed child, got %+v", children)
+	}
+}
+
+func TestRecordIntent_UnconfirmedInSessionIsKept(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&TestCR{}).Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.RecordIntent(ctx, deploymentGVK, client.ObjectKey{Namespace: "default", Name: "pending"}); err != nil {
+		t.Fatalf("RecordIntent failed: %v", err)
+	}
+	result, err := pruner.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if got := result.Children[0]; got.Outcome != OutcomeKept || got.Reason != ReasonIntentPending {
+		t.Errorf("Expected the pending child to be kept, got %+v", got)
+	}
+}
+
+func TestRecordIntent_CrashedReconcileIsPruned(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&TestCR{}).Build()
+	deps := createDeployments(t, cl, "kept")
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if err := pruner.Persist(ctx); err != nil {
+		t.Fatalf("Persist failed: %v", err)
+	}
+
+	// The controller records and creates a child, then crashes
+	leaked := newTestDeployment("leaked")
+	owner = getStoredOwner(t, cl)
+	pruner = NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.RecordIntent(ctx, deploymentGVK, client.ObjectKeyFromObject(leaked)); err != nil {
+		t.Fatalf("RecordIntent failed: %v", err)
+	}
+	if err := cl.Create(ctx, leaked); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	// After the restart, the generation has not moved: the child is kept
+	owner = getStoredOwner(t, cl)
+	pruner = NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	result, err := markAndPrune(t, pruner, deps...)
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if result.Counts[OutcomeDeleted] != 0 {
+		t.Errorf("Expected nothing to be deleted, got %+v", result.Children)
+	}
+
+	// The generation moves and the child is not desired anymore
+	owner.SetGeneration(2)
+	pruner = NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	result, err = markAndPrune(t, pruner, deps...)
+	if err != nil {
+		t.Fatalf("Third Prune failed: %v", err)
+	}
+	if result.Counts[OutcomeDeleted] != 1 {
+		t.Errorf("Expected the leaked child to be deleted, got %+v", result.Children)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(leaked), &appsv1.Deployment{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the leaked child to be gone, got %v", err)
+	}
+}
+
+func TestRecordIntent_ReRecordedAfterCrashIsKept(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&TestCR{}).Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	// The controller records a child, then crashes before applying it
+	key := client.ObjectKey{Namespace: "default", Name: "pending"}
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.RecordIntent(ctx, deploymentGVK, key); err != nil {
+		t.Fatalf("RecordIntent failed: %v", err)
+	}
+
+	// The next generation records it again before applying it
+	owner = getStoredOwner(t, cl)
+	owner.SetGeneration(2)
+	pruner = NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.RecordIntent(ctx, deploymentGVK, key); err != nil {
+		t.Fatalf("RecordIntent failed: %v", err)
+	}
+	result, err := pruner.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if got := result.Children[0]; got.Outcome != OutcomeKept || got.Reason != ReasonIntentPending {
+		t.Errorf("Expected the pending child to be kept, got %+v", got)
+	}
+
+	children := getStoredOwner(t, cl).Status.Inventory.Children
+	if len(children) != 1 || children[0].State != ChildStatePending || children[0].ObservedGeneration != 2 {
+		t.Errorf("Expected the intent to be persisted at generation 2, got %+v", children)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrPruneThresholdExceeded is returned by Prune when a PruneLimits threshold
+// trips. Nothing is deleted in that case. Use errors.As with a
+// *PruneThresholdError to get the children that would have been deleted.
+var ErrPruneThresholdExceeded = errors.New("prune threshold exceeded")
+
+// PruneLimits caps how much a single Prune call may delete.
+// A zero MaxCount or MaxPercent disables the corresponding check. Once limits
+// are set, Prune also refuses to delete anything when no child was marked as
+// reconciled in the session, unless AllowEmptyDesiredSet is set.
+//
+// Example:
+//
+//	WithPruneLimits(PruneLimits{MaxCount: 10, MaxPercent: 50})
+type PruneLimits struct {
+	// MaxCount is the maximum number of children deleted in one Prune call.
+	MaxCount int
+
+	// MaxPercent is the maximum share of the inventory, in percent, deleted in
+	// one Prune call. It is computed against the inventory as it was before
+	// the session: children added in the session are not counted.
+	MaxPercent int
+
+	// AllowEmptyDesiredSet lets Prune delete children when no child was
+	// marked with MarkReconciled during the session, for owners whose desired
+	// set may legitimately become empty.
+	AllowEmptyDesiredSet bool
+}
+
+// PruneThresholdError describes a Prune call refused by PruneLimits.
+// It matches ErrPruneThresholdExceeded with errors.Is.
+type PruneThresholdError struct {
+	// Reason is the threshold that tripped.
+	Reason string
+
+	// Candidates are the children Prune would have deleted.
+	Candidates []corev1.ObjectReference
+
+	// Total is the number of children in the inventory before the session.
+	Total int
+}
+
+// Error implements error.
+func (e *PruneThresholdError) Error() string {
+	return fmt.Sprintf("%s: %s (%d of %d children would be deleted)",
+		ErrPruneThresholdExceeded, e.Reason, len(e.Candidates), e.Total)
+}
+
+// Is reports whether target is ErrPruneThresholdExceeded.
+func (e *PruneThresholdError) Is(target error) bool {
+	return target == ErrPruneThresholdExceeded
+}
+
+// checkPruneLimits returns a *PruneThresholdError if deleting candidates out
+// of an inventory of total children would exceed the pruner's limits.
+func (p *Pruner) checkPruneLimits(candidates []ManagedChild, total int) error {
+	if p.limits == nil || len(candidates) == 0 {
+		return nil
+	}
+
+	var reason string
+	switch {
+	case !p.limits.AllowEmptyDesiredSet && len(p.desiredRefs) == 0:
+		reason = "no child was marked as reconciled"
+	case p.limits.MaxCount > 0 && len(candidates) > p.limits.MaxCount:
+		reason = fmt.Sprintf("more than %d children", p.limits.MaxCount)
+	case p.limits.MaxPercent > 0 && len(candidates)*100 > p.limits.MaxPercent*total:
+		reason = fmt.Sprintf("more than %d%% of the inventory", p.limits.MaxPercent)
+	default:
+		return nil
+	}
+
+	refs := make([]corev1.ObjectReference, 0, len(candidates))
+	for _, child := range candidates {
+		refs = append(refs, child.ObjectReference)
+	}
+	return &PruneThresholdError{Reason: reason, Candidates: refs, Total: total}
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPruneLimits_MaxCountRefusesPrune(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	objs := []client.Object{
+		newTestDeployment("test-deployment-1"),
+		newTestDeployment("test-deployment-2"),
+		newTestDeployment("test-deployment-3"),
+	}
+	_, err := pruneAfterRemoval(t, cl, owner, objs, WithScheme(scheme), WithPruneLimits(PruneLimits{MaxCount: 2, AllowEmptyDesiredSet: true}))
+
+	if !errors.Is(err, ErrPruneThresholdExceeded) {
+		t.Fatalf("Expected ErrPruneThresholdExceeded, got %v", err)
+	}
+	var thresholdErr *PruneThresholdError
+	if !errors.As(err, &thresholdErr) {
+		t.Fatalf("Expected a *PruneThresholdError, got %T", err)
+	}
+	if len(thresholdErr.Candidates) != 3 || thresholdErr.Total != 3 {
+		t.Errorf("Expected 3 of 3 candidates, got %d of %d", len(thresholdErr.Candidates), thresholdErr.Total)
+	}
+
+	// Nothing was deleted and the generation stays open
+	for _, obj := range objs {
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(obj), &appsv1.Deployment{}); err != nil {
+			t.Errorf("Expected %s to still exist, got %v", obj.GetName(), err)
+		}
+	}
+	if len(owner.Status.Inventory.Children) != 3 {
+		t.Errorf("Expected the inventory to be untouched, got %+v", owner.Status.Inventory.Children)
+	}
+	if got := owner.Status.Inventory.CompletedGeneration; got != 1 {
+		t.Errorf("Expected CompletedGeneration to stay 1, got %d", got)
+	}
+}
+
+func TestPruneLimits_MaxPercent(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	dep1 := newTestDeployment("test-deployment-1")
+	dep2 := newTestDeployment("test-deployment-2")
+	opts := []Option{WithScheme(scheme), WithPruneLimits(PruneLimits{MaxPercent: 50})}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	for _, dep := range []*appsv1.Deployment{dep1, dep2} {
+		if err := cl.Create(ctx, dep); err != nil {
+			t.Fatalf("Failed to create deployment: %v", err)
+		}
+		if err := pruner.MarkReconciled(dep); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Pruning half of the inventory is within the limit
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	if err := pruner2.MarkReconciled(dep2); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	result, err := pruner2.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if len(result.Pruned()) != 1 || result.Pruned()[0].Name != dep1.Name {
+		t.Errorf("Expected %s to be pruned, got %+v", dep1.Name, result.Pruned())
+	}
+
+	// Pruning the whole inventory is not
+	owner.SetGeneration(3)
+	dep3 := newTestDeployment("test-deployment-3")
+	if err := cl.Create(ctx, dep3); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+	pruner3 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	if _, err := markAndPrune(t, pruner3, dep2, dep3); err != nil {
+		t.Fatalf("Third Prune failed: %v", err)
+	}
+	owner.SetGeneration(4)
+	pruner4 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	if _, err := pruner4.Prune(ctx); !errors.Is(err, ErrPruneThresholdExceeded) {
+		t.Errorf("Expected ErrPruneThresholdExceeded, got %v", err)
+	}
+}
+
+func TestPruneLimits_MaxPercentIgnoresAddedChildren(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	opts := []Option{WithScheme(scheme), WithPruneLimits(PruneLimits{MaxPercent: 50})}
+	old := createDeployments(t, cl, "old-1", "old-2", "old-3", "old-4")
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	if _, err := markAndPrune(t, pruner, old...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Every child is renamed: 4 of 8 children, but all of the previous inventory
+	owner.SetGeneration(2)
+	renamed := createDeployments(t, cl, "new-1", "new-2", "new-3", "new-4")
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	_, err := markAndPrune(t, pruner2, renamed...)
+
+	var thresholdErr *PruneThresholdError
+	if !errors.As(err, &thresholdErr) {
+		t.Fatalf("Expected a *PruneThresholdError, got %v", err)
+	}
+	if len(thresholdErr.Candidates) != 4 || thresholdErr.Total != 4 {
+		t.Errorf("Expected 4 of 4 candidates, got %d of %d", len(thresholdErr.Candidates), thresholdErr.Total)
+	}
+	for _, dep := range old {
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(dep), &appsv1.Deployment{}); err != nil {
+			t.Errorf("Expected %s to still exist, got %v", dep.GetName(), err)
+		}
+	}
+}
+
+func TestPruneLimits_EmptyDesiredSet(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	// Refused by default once limits are set
+	dep := newTestDeployment("test-deployment")
+	_, err := pruneAfterRemoval(t, cl, newTestOwner(1), []client.Object{dep},
+		WithScheme(scheme),
+		WithPruneLimits(PruneLimits{MaxCount: 10}),
+	)
+	if !errors.Is(err, ErrPruneThresholdExceeded) {
+		t.Fatalf("Expected ErrPruneThresholdExceeded, got %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(dep), &appsv1.Deployment{}); err != nil {
+		t.Errorf("Expected the deployment to still exist, got %v", err)
+	}
+
+	// Allowed explicitly
+	other := newTestDeployment("other-deployment")
+	_, err = pruneAfterRemoval(t, cl, newTestOwner(1), []client.Object{other},
+		WithScheme(scheme),
+		WithPruneLimits(PruneLimits{MaxCount: 10, AllowEmptyDesiredSet: true}),
+	)
+	if err != nil {
+		t.Fatalf("Expected Prune to succeed, got %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(other), &appsv1.Deployment{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the deployment to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// logLevelDebug is the verbosity of decisions to keep a child and of marked
+// children. Deletions, skips and failures are logged at the default level.
+const logLevelDebug = 1
+
+// logger returns the logger injected with WithLogger, or the one from the
+// context, annotated with the owner and its generation.
+func (p *Pruner) logger(ctx context.Context) logr.Logger {
+	logger := log.FromContext(ctx)
+	if p.log != nil {
+		logger = *p.log
+	}
+	return logger.WithValues(
+		"owner", client.ObjectKeyFromObject(p.owner),
+		"generation", p.generation,
+		"lastAppliedGeneration", p.lastAppliedGen,
+	)
+}
+
+// childLogValues returns the key/value pairs identifying a child in log lines.
+func childLogValues(ref corev1.ObjectReference) []any {
+	return []any{
+		"childGVK", ref.GroupVersionKind().String(),
+		"childNamespace", ref.Namespace,
+		"childName", ref.Name,
+	}
+}
+
+// logDecisions logs what Prune decided for each inventory child.
+func (p *Pruner) logDecisions(ctx context.Context, children ManagedChildrenList, result *PruneResult) {
+	logger := p.logger(ctx)
+
+	for i, child := range result.Children {
+		values := append(childLogValues(child.ObjectReference),
+			"childGeneration", children[i].ObservedGeneration,
+			"decision", child.Outcome,
+			"reason", child.Reason,
+		)
+
+		switch child.Outcome {
+		case OutcomeKept:
+			logger.V(logLevelDebug).Info("Keeping child", values...)
+		case OutcomeFailed:
+			logger.Error(child.Error, "Failed to prune child", values...)
+		case OutcomeIgnored:
+			logger.Info("Ignored error while pruning child", append(values, "error", child.Error.Error())...)
+		case OutcomeSkipped:
+			logger.Info("Skipped pruning child", values...)
+		case OutcomeDeleting:
+			logger.Info("Waiting for child deletion", values...)
+		default:
+			logger.Info("Pruned child", values...)
+		}
+	}
+
+	logger.V(logLevelDebug).Info("Prune finished", "counts", result.Counts, "requeueAfter", result.RequeueAfter)
+}
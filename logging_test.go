@@ -0,0 +1,111 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// newCaptureLogger returns a logger that records every line up to verbosity 1.
+func newCaptureLogger(lines *[]string) logr.Logger {
+	return funcr.New(func(prefix, args string) {
+		*lines = append(*lines, args)
+	}, funcr.Options{Verbosity: 1})
+}
+
+func TestLogging_PruneDecisions(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	kept := newTestDeployment("kept")
+	stale := newTestDeployment("stale")
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	for _, dep := range []*appsv1.Deployment{kept, stale} {
+		if err := cl.Create(ctx, dep); err != nil {
+			t.Fatalf("Failed to create deployment: %v", err)
+		}
+		if err := pruner.MarkReconciled(dep); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// The logger is taken from the context
+	var lines []string
+	ctx = log.IntoContext(ctx, newCaptureLogger(&lines))
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner2.MarkReconciled(kept); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner2.Prune(ctx); err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	want := []string{
+		`"msg"="Keeping child" "owner"={"name"="test-owner" "namespace"="default"} "generation"=2 "lastAppliedGeneration"=1 ` +
+			`"childGVK"="apps/v1, Kind=Deployment" "childNamespace"="default" "childName"="kept" "childGeneration"=2 "decision"="Kept" "reason"="marked as reconciled"`,
+		`"msg"="Pruned child" "owner"={"name"="test-owner" "namespace"="default"} "generation"=2 "lastAppliedGeneration"=1 ` +
+			`"childGVK"="apps/v1, Kind=Deployment" "childNamespace"="default" "childName"="stale" "childGeneration"=1 "decision"="Deleted" "reason"="not reconciled in the current generation"`,
+	}
+	for _, w := range want {
+		found := false
+		for _, line := range lines {
+			if strings.Contains(line, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected a log line containing %s, got:\n%s", w, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+func TestLogging_WithLogger(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	var lines []string
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithLogger(newCaptureLogger(&lines)))
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+
+	if len(lines) != 1 || !strings.Contains(lines[0], `"msg"="Marked child as reconciled"`) ||
+		!strings.Contains(lines[0], `"childName"="test-deployment"`) {
+		t.Errorf("Expected MarkReconciled to log through the injected logger, got %q", lines)
+	}
+}
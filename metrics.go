@@ -0,0 +1,149 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// deletionsTotal counts the children Prune acted on, by GVK and outcome.
+	deletionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "reconcileprune_deletions_total",
+		Help: "Number of children handled by Prune, by group, version, kind and outcome.",
+	}, []string{"group", "version", "kind", "outcome"})
+
+	// pruneDuration observes how long Prune calls take.
+	pruneDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "reconcileprune_prune_duration_seconds",
+		Help:    "Duration of Prune calls in seconds, by owner kind.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"owner_kind"})
+
+	// inventorySize reports the number of tracked children across all owners of a kind.
+	inventorySize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "reconcileprune_inventory_size",
+		Help: "Number of children tracked in the inventories of all owners of a kind.",
+	}, []string{"owner_kind"})
+
+	// thresholdTripsTotal counts Prune calls refused by PruneLimits.
+	thresholdTripsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "reconcileprune_threshold_trips_total",
+		Help: "Number of Prune calls refused by prune limits, by owner kind.",
+	}, []string{"owner_kind"})
+
+	registerMetricsOnce sync.Once
+
+	// inventorySizes holds the last inventory size of each owner, by owner kind
+	// and key, so that inventorySize can be reported per kind.
+	inventorySizesMu sync.Mutex
+	inventorySizes   = map[string]map[types.NamespacedName]int{}
+)
+
+// registerMetrics registers the pruner metrics on the controller-runtime registry.
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		metrics.Registry.MustRegister(deletionsTotal, pruneDuration, inventorySize, thresholdTripsTotal)
+	})
+}
+
+// recordMetrics updates the pruner metrics after a Prune call.
+func (p *Pruner) recordMetrics(result *PruneResult, duration time.Duration, thresholdTripped bool) {
+	if !p.metrics {
+		return
+	}
+
+	ownerKind := p.ownerGroupKind().String()
+	pruneDuration.WithLabelValues(ownerKind).Observe(duration.Seconds())
+	if thresholdTripped {
+		thresholdTripsTotal.WithLabelValues(ownerKind).Inc()
+	}
+
+	for _, child := range result.Children {
+		if child.Outcome == OutcomeKept {
+			continue
+		}
+		gvk := schema.FromAPIVersionAndKind(child.ObjectReference.APIVersion, child.ObjectReference.Kind)
+		deletionsTotal.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, string(child.Outcome)).Inc()
+	}
+
+	p.recordInventorySize()
+}
+
+// recordInventorySize reports the owner's inventory size in the inventorySize
+// gauge of its kind. Owners whose inventory is empty, typically after
+// Teardown, are forgotten so that deleted owners do not accumulate.
+func (p *Pruner) recordInventorySize() {
+	if !p.metrics {
+		return
+	}
+	setInventorySize(p.ownerGroupKind(), types.NamespacedName{Namespace: p.owner.GetNamespace(), Name: p.owner.GetName()}, len(*p.statusChildren))
+}
+
+// ForgetOwnerMetrics removes an owner from the reconcileprune_inventory_size
+// gauge. Owners emptied by Teardown or PruneAll are forgotten automatically,
+// but an owner deleted without them, for example by the garbage collector,
+// keeps counting with its last inventory size until it is forgotten. Call
+// ForgetOwnerMetrics when the reconciler finds that the owner is gone.
+//
+// Example:
+//
+//	if err := r.Get(ctx, req.NamespacedName, &myCR); apierrors.IsNotFound(err) {
+//	    reconcileprune.ForgetOwnerMetrics(schema.GroupKind{Group: "example.com", Kind: "MyApp"}, req.NamespacedName)
+//	    return ctrl.Result{}, nil
+//	}
+func ForgetOwnerMetrics(ownerKind schema.GroupKind, key types.NamespacedName) {
+	setInventorySize(ownerKind, key, 0)
+}
+
+// setInventorySize records the inventory size of the owner identified by
+// ownerKind and key, forgetting it when n is zero, and updates the gauge of
+// its kind.
+func setInventorySize(ownerKind schema.GroupKind, key types.NamespacedName, n int) {
+	kind := ownerKind.String()
+	inventorySizesMu.Lock()
+	defer inventorySizesMu.Unlock()
+	sizes, ok := inventorySizes[kind]
+	if !ok {
+		sizes = map[types.NamespacedName]int{}
+		inventorySizes[kind] = sizes
+	}
+	if n > 0 {
+		sizes[key] = n
+	} else {
+		delete(sizes, key)
+	}
+	total := 0
+	for _, n := range sizes {
+		total += n
+	}
+	if len(sizes) == 0 {
+		delete(inventorySizes, kind)
+	}
+	inventorySize.WithLabelValues(kind).Set(float64(total))
+}
+
+// ownerGroupKind returns the owner's GroupKind, resolving it through the
+// scheme when the owner has no TypeMeta.
+func (p *Pruner) ownerGroupKind() schema.GroupKind {
+	gvk, _ := p.gvkFor(p.owner)
+	return gvk.GroupKind()
+}
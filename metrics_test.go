@@ -0,0 +1,186 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// resetMetrics clears the package-level metrics before and after the test, so
+// that metric tests do not depend on the order they run in.
+func resetMetrics(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		deletionsTotal.Reset()
+		pruneDuration.Reset()
+		inventorySize.Reset()
+		thresholdTripsTotal.Reset()
+
+		inventorySizesMu.Lock()
+		defer inventorySizesMu.Unlock()
+		inventorySizes = map[string]map[types.NamespacedName]int{}
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestMetrics_Prune(t *testing.T) {
+	resetMetrics(t)
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	owner.UID = "metrics-owner-uid"
+	kept := newTestDeployment("kept")
+	stale := newTestDeployment("stale")
+	opts := []Option{WithScheme(scheme), WithMetrics()}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	for _, dep := range []client.Object{kept, stale} {
+		if err := cl.Create(ctx, dep); err != nil {
+			t.Fatalf("Failed to create deployment: %v", err)
+		}
+		if err := pruner.MarkReconciled(dep); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	if err := pruner2.MarkReconciled(kept); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner2.Prune(ctx); err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	deleted := deletionsTotal.WithLabelValues("apps", "v1", "Deployment", string(OutcomeDeleted))
+	if got := testutil.ToFloat64(deleted); got != 1 {
+		t.Errorf("Expected 1 deletion to be counted, got %v", got)
+	}
+	if got := testutil.CollectAndCount(pruneDuration); got != 1 {
+		t.Errorf("Expected Prune durations to be observed, got %d series", got)
+	}
+	// Only the kept deployment remains in the owner's inventory; the TestCR
+	// TypeMeta has no group
+	if got := testutil.ToFloat64(inventorySize.WithLabelValues("TestCR")); got != 1 {
+		t.Errorf("Expected an inventory size of 1, got %v", got)
+	}
+
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	registered := map[string]bool{}
+	for _, family := range families {
+		registered[family.GetName()] = true
+	}
+	for _, name := range []string{"reconcileprune_deletions_total", "reconcileprune_prune_duration_seconds", "reconcileprune_inventory_size"} {
+		if !registered[name] {
+			t.Errorf("Expected %s to be registered on the controller-runtime registry", name)
+		}
+	}
+}
+
+func TestMetrics_ThresholdTrips(t *testing.T) {
+	resetMetrics(t)
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	_, err := pruneAfterRemoval(t, cl, newTestOwner(1), []client.Object{newTestDeployment("test-deployment")},
+		WithScheme(scheme),
+		WithMetrics(),
+		WithPruneLimits(PruneLimits{}),
+	)
+	if !errors.Is(err, ErrPruneThresholdExceeded) {
+		t.Fatalf("Expected ErrPruneThresholdExceeded, got %v", err)
+	}
+
+	if got := testutil.ToFloat64(thresholdTripsTotal.WithLabelValues("TestCR")); got != 1 {
+		t.Errorf("Expected 1 threshold trip to be counted, got %v", got)
+	}
+}
+
+func TestMetrics_PruneAllForgetsOwner(t *testing.T) {
+	resetMetrics(t)
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "test-deployment")
+
+	owner := newTestOwner(1)
+	opts := []Option{WithScheme(scheme), WithMetrics()}
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if got := testutil.ToFloat64(inventorySize.WithLabelValues("TestCR")); got != 1 {
+		t.Fatalf("Expected an inventory size of 1, got %v", got)
+	}
+
+	// The owner is deleted and its inventory emptied
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	if _, err := pruner2.PruneAll(ctx); err != nil {
+		t.Fatalf("PruneAll failed: %v", err)
+	}
+	if got := testutil.ToFloat64(inventorySize.WithLabelValues("TestCR")); got != 0 {
+		t.Errorf("Expected an inventory size of 0, got %v", got)
+	}
+	inventorySizesMu.Lock()
+	defer inventorySizesMu.Unlock()
+	if sizes, ok := inventorySizes["TestCR"]; ok {
+		t.Errorf("Expected the owner to be forgotten, got %v", sizes)
+	}
+}
+
+func TestMetrics_ForgetOwnerMetrics(t *testing.T) {
+	resetMetrics(t)
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "first", "second")
+
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithMetrics())
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if got := testutil.ToFloat64(inventorySize.WithLabelValues("TestCR")); got != 2 {
+		t.Fatalf("Expected an inventory size of 2, got %v", got)
+	}
+
+	// The owner is garbage collected without a Teardown
+	ForgetOwnerMetrics(schema.GroupKind{Kind: "TestCR"}, types.NamespacedName{Namespace: "default", Name: "test-owner"})
+	if got := testutil.ToFloat64(inventorySize.WithLabelValues("TestCR")); got != 0 {
+		t.Errorf("Expected an inventory size of 0, got %v", got)
+	}
+	inventorySizesMu.Lock()
+	defer inventorySizesMu.Unlock()
+	if sizes, ok := inventorySizes["TestCR"]; ok {
+		t.Errorf("Expected the owner to be forgotten, got %v", sizes)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import "errors"
+
+// PruneMode selects when Prune deletes the children that were not marked as
+// reconciled in the session.
+type PruneMode string
+
+const (
+	// PruneOnGenerationChange prunes when the owner's metadata.generation
+	// increases. It suits controllers whose desired set only depends on the
+	// owner's spec.
+	PruneOnGenerationChange PruneMode = "OnGenerationChange"
+
+	// PruneAlways prunes on every Prune call: every child that was not marked
+	// in the session is deleted, including children applied earlier in the
+	// current generation. Every desired child must be marked before Prune.
+	PruneAlways PruneMode = "Always"
+
+	// PruneOnFingerprintChange prunes when the owner's generation increases or
+	// when the fingerprint set with WithFingerprint differs from the one stored
+	// in the inventory. It suits controllers whose desired set also depends on
+	// other inputs, such as referenced ConfigMaps, Nodes or external APIs.
+	// It requires an Inventory, see NewInventoryPruner.
+	PruneOnFingerprintChange PruneMode = "OnFingerprintChange"
+)
+
+// errFingerprintWithoutInventory is returned by Prune when PruneOnFingerprintChange
+// EpochFromSpecHash or EpochFromResourceVersion is used without an Inventory to
+// store the fingerprint in.
+var errFingerprintWithoutInventory = errors.New("pruning on fingerprint, spec hash or resourceVersion change requires an inventory, use NewInventoryPruner or NewPrunerWithStore")
+
+// shouldPrune reports whether Prune deletes stale children in this session.
+func (p *Pruner) shouldPrune(currentGen int64) bool {
+	switch {
+	case p.pruneMode == PruneAlways:
+		return true
+	case currentGen > p.lastAppliedGen:
+		return true
+	default:
+		return p.tracksFingerprint() && p.sessionFingerprint() != p.inventory.Fingerprint
+	}
+}
+
+// tracksFingerprint reports whether Prune compares and stores a fingerprint.
+func (p *Pruner) tracksFingerprint() bool {
+	return p.pruneMode == PruneOnFingerprintChange || p.epochKey != ""
+}
+
+// sessionFingerprint returns the fingerprint of the session: the one set with
+// WithFingerprint, combined with the owner's fingerprint under EpochFromSpecHash
+// or EpochFromResourceVersion.
+func (p *Pruner) sessionFingerprint() string {
+	switch {
+	case p.epochKey == "":
+		return p.fingerprint
+	case p.fingerprint == "":
+		return p.epochKey
+	default:
+		return p.fingerprint + "/" + p.epochKey
+	}
+}
+
+// unchangedReason returns the reason reported for children kept because
+// nothing prompted a prune.
+func (p *Pruner) unchangedReason() string {
+	if p.tracksFingerprint() {
+		return ReasonFingerprintUnchanged
+	}
+	return ReasonGenerationUnchanged
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// markAndPrune runs a session marking the given deployments.
+func markAndPrune(t *testing.T, pruner *Pruner, deployments ...*appsv1.Deployment) (*PruneResult, error) {
+	t.Helper()
+	for _, dep := range deployments {
+		if err := pruner.MarkReconciled(dep); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	return pruner.Prune(context.Background())
+}
+
+// createDeployments creates deployments with the given names.
+func createDeployments(t *testing.T, cl client.Client, names ...string) []*appsv1.Deployment {
+	t.Helper()
+	deployments := make([]*appsv1.Deployment, 0, len(names))
+	for _, name := range names {
+		dep := newTestDeployment(name)
+		if err := cl.Create(context.Background(), dep); err != nil {
+			t.Fatalf("Failed to create deployment: %v", err)
+		}
+		deployments = append(deployments, dep)
+	}
+	return deployments
+}
+
+func TestPruneMode_Always(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "kept", "unmarked")
+
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithPruneMode(PruneAlways))
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Same generation: the unmarked child is pruned anyway
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithPruneMode(PruneAlways))
+	result, err := markAndPrune(t, pruner2, deps[0])
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if result.Counts[OutcomeDeleted] != 1 {
+		t.Errorf("Expected the unmarked child to be deleted, got %+v", result.Children)
+	}
+	if names := inventoryNames(owner.Status.Inventory.Children); len(names) != 1 || names[0] != "kept" {
+		t.Errorf("Expected only the marked child in the inventory, got %v", names)
+	}
+}
+
+func TestPruneMode_Fingerprint(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "kept", "unmarked")
+
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithFingerprint("v1"))
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if owner.Status.Inventory.Fingerprint != "v1" {
+		t.Errorf("Expected fingerprint v1 to be stored, got %q", owner.Status.Inventory.Fingerprint)
+	}
+
+	// Same generation and fingerprint: nothing is pruned
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithFingerprint("v1"))
+	result, err := markAndPrune(t, pruner2, deps[0])
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if got := result.Children[1]; got.Outcome != OutcomeKept || got.Reason != ReasonFingerprintUnchanged {
+		t.Errorf("Expected the unmarked child to be kept, got %+v", got)
+	}
+
+	// The inputs changed: the unmarked child is pruned
+	pruner3 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithFingerprint("v2"))
+	result, err = markAndPrune(t, pruner3, deps[0])
+	if err != nil {
+		t.Fatalf("Third Prune failed: %v", err)
+	}
+	if result.Counts[OutcomeDeleted] != 1 {
+		t.Errorf("Expected the unmarked child to be deleted, got %+v", result.Children)
+	}
+	if inventory := owner.Status.Inventory; inventory.Fingerprint != "v2" || inventory.CompletedGeneration != 1 {
+		t.Errorf("Expected fingerprint v2 at generation 1, got %q at %d", inventory.Fingerprint, inventory.CompletedGeneration)
+	}
+}
+
+func TestPruneMode_FingerprintRequiresInventory(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "test-deployment")
+
+	owner := newTestOwner(1)
+	pruner := NewPruner(cl, owner, &owner.Status.Children, WithScheme(scheme), WithFingerprint("v1"))
+	if _, err := markAndPrune(t, pruner, deps...); !errors.Is(err, errFingerprintWithoutInventory) {
+		t.Errorf("Expected Prune to require an inventory, got %v", err)
+	}
+}
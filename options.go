@@ -17,8 +17,13 @@ package reconcileprune
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -37,18 +42,220 @@ func WithScheme(scheme *runtime.Scheme) Option {
 	}
 }
 
+// WithFieldOwner sets the field manager used by Apply for server-side apply.
+// It is required to use Apply.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children, WithFieldOwner("my-controller"))
+func WithFieldOwner(fieldOwner string) Option {
+	return func(p *Pruner) {
+		p.fieldOwner = fieldOwner
+	}
+}
+
 // WithDryRun enables dry-run mode where delete operations are simulated.
 // Uses Kubernetes dry-run to validate deletions without actually removing resources.
 // Resources that would be pruned are returned in the Result.
+// Dry-run composes with WithDeleteOptions and WithPruneRule.
 //
 // Example:
 //
 //	pruner := NewPruner(client, WithDryRun(true))
 func WithDryRun(dryRun bool) Option {
 	return func(p *Pruner) {
-		if dryRun {
-			p.deleteOpts = []client.DeleteOption{client.DryRunAll}
+		p.dryRun = dryRun
+	}
+}
+
+// WithDeleteOptions sets delete options sent with every prune delete call,
+// such as a propagation policy or a grace period.
+// Options from a matching PruneRule are applied after these.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children,
+//	    WithDeleteOptions(client.PropagationPolicy(metav1.DeletePropagationForeground)),
+//	)
+func WithDeleteOptions(opts ...client.DeleteOption) Option {
+	return func(p *Pruner) {
+		p.deleteOpts = append(p.deleteOpts, opts...)
+	}
+}
+
+// WithConcurrency sets how many children are deleted in parallel.
+// Deletion waves are still processed one after the other, and the error
+// handler is still called sequentially, in inventory order.
+//
+// Default: 1 (sequential deletion).
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children, WithConcurrency(10))
+func WithConcurrency(n int) Option {
+	return func(p *Pruner) {
+		p.concurrency = n
+	}
+}
+
+// WithPruneMode sets when Prune deletes the children that were not marked as
+// reconciled in the session.
+//
+// Default: PruneOnGenerationChange.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children, WithPruneMode(PruneAlways))
+func WithPruneMode(mode PruneMode) Option {
+	return func(p *Pruner) {
+		p.pruneMode = mode
+	}
+}
+
+// WithFingerprint selects PruneOnFingerprintChange with the given fingerprint,
+// a caller-computed hash of every input the desired set depends on. Prune
+// runs when it differs from the fingerprint stored in the inventory, and
+// stores it once the prune completes.
+//
+// Example:
+//
+//	h := sha256.New()
+//	h.Write([]byte(configMap.ResourceVersion))
+//	pruner := NewInventoryPruner(client, owner, &owner.Status.Inventory,
+//	    WithFingerprint(hex.EncodeToString(h.Sum(nil))))
+func WithFingerprint(fingerprint string) Option {
+	return func(p *Pruner) {
+		p.pruneMode = PruneOnFingerprintChange
+		p.fingerprint = fingerprint
+	}
+}
+
+// WithEpochSource sets what stands in for the owner's generation when its
+// metadata.generation is 0. The inventory format is unchanged: children and
+// the completed generation record a counter epoch instead, and a spec hash or
+// resourceVersion is recorded as the inventory's Fingerprint.
+//
+// Default: none, Prune never runs for an owner without a generation unless
+// another prune mode is selected.
+//
+// Example:
+//
+//	pruner, err := NewPrunerWithStore(ctx, client, configMap, store,
+//	    WithEpochSource(EpochFromResourceVersion()))
+func WithEpochSource(source EpochSource) Option {
+	return func(p *Pruner) {
+		p.epochSource = source
+	}
+}
+
+// WithPruneLimits sets safety thresholds for Prune. When a threshold trips,
+// Prune deletes nothing and returns a *PruneThresholdError matching
+// ErrPruneThresholdExceeded. Children already being deleted are not counted.
+// With limits set, a session that marked no child prunes nothing unless
+// PruneLimits.AllowEmptyDesiredSet is set.
+//
+// Default: no limits.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children,
+//	    WithPruneLimits(PruneLimits{MaxPercent: 50}))
+func WithPruneLimits(limits PruneLimits) Option {
+	return func(p *Pruner) {
+		p.limits = &limits
+	}
+}
+
+// WithEventRecorder emits Kubernetes events on the owner for prune activity:
+// a Normal "Pruned" event per pruned child, a Warning "PruneFailed" event per
+// failed deletion, a Normal "PruneSkipped" event per child left in place, a
+// "PruneSummary" event for each Prune call that acted on a child, and a Warning
+// "PruneThresholdExceeded" event when PruneLimits refused the Prune call.
+//
+// Default: no events.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children,
+//	    WithEventRecorder(mgr.GetEventRecorderFor("my-controller")))
+func WithEventRecorder(recorder record.EventRecorder) Option {
+	return func(p *Pruner) {
+		p.recorder = recorder
+	}
+}
+
+// WithMetrics enables Prometheus metrics for the pruner. The metrics are
+// registered on the controller-runtime metrics registry the first time the
+// option is used, and are exposed by the manager's metrics endpoint:
+//   - reconcileprune_deletions_total: children handled by Prune, by GVK and outcome
+//   - reconcileprune_prune_duration_seconds: duration of Prune calls, by owner kind
+//   - reconcileprune_inventory_size: tracked children of all owners of a kind,
+//     see ForgetOwnerMetrics for owners deleted without Teardown
+//   - reconcileprune_threshold_trips_total: Prune calls refused by PruneLimits
+//
+// Default: disabled.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children, WithMetrics())
+func WithMetrics() Option {
+	return func(p *Pruner) {
+		registerMetrics()
+		p.metrics = true
+	}
+}
+
+// WithLogger sets the logger used by the pruner. Prune logs each decision
+// with the owner key, the child GVK, namespace and name, the generations
+// involved and the outcome: deletions, skips and failures at the default
+// level, kept children at V(1).
+//
+// Default: the logger from the context passed to Prune, see log.FromContext.
+// MarkReconciled has no context and falls back to controller-runtime's global
+// logger.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children,
+//	    WithLogger(log.FromContext(ctx).WithName("pruner")))
+func WithLogger(logger logr.Logger) Option {
+	return func(p *Pruner) {
+		p.log = &logger
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider used by the pruner.
+// Prune opens a "reconcileprune.Prune" span with the owner, its generations and
+// the outcome counts as attributes, with a "reconcileprune.Delete" child span
+// for each child it deletes.
+//
+// Default: the global tracer provider, see otel.GetTracerProvider.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children, WithTracerProvider(tp))
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(p *Pruner) {
+		p.tracerProvider = tp
+	}
+}
+
+// WithPruneRule registers a rule for children of the given GroupKind.
+// A later rule for the same GroupKind replaces the earlier one.
+//
+// Example:
+//
+//	orphan := metav1.DeletePropagationOrphan
+//	pruner := NewPruner(client, owner, &owner.Status.Children,
+//	    WithPruneRule(schema.GroupKind{Kind: "PersistentVolumeClaim"}, PruneRule{Protected: true}),
+//	    WithPruneRule(schema.GroupKind{Group: "batch", Kind: "Job"}, PruneRule{PropagationPolicy: &orphan}),
+//	)
+func WithPruneRule(gk schema.GroupKind, rule PruneRule) Option {
+	return func(p *Pruner) {
+		if p.rules == nil {
+			p.rules = make(map[schema.GroupKind]PruneRule)
 		}
+		p.rules[gk] = rule
 	}
 }
 
@@ -71,6 +278,92 @@ func WithErrorHandler(handler ErrorHandlerFunc) Option {
 	}
 }
 
+// WithDeletionOrder sets the order in which stale children are deleted.
+// Each wave must complete before the next one starts, possibly across several
+// reconciles. Pass nil to delete all children in a single wave.
+//
+// Default: DefaultDeletionOrder.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children, WithDeletionOrder(DeletionOrder{
+//	    {{Group: "example.com", Kind: "Database"}},
+//	    {{Group: "", Kind: "Secret"}},
+//	}))
+func WithDeletionOrder(order DeletionOrder) Option {
+	return func(p *Pruner) {
+		p.deletionOrder = order
+	}
+}
+
+// WithWaitForDeletion keeps pruned children in the inventory, in the Deleting
+// state, until a follow-up read confirms they are gone. While some children are
+// still being deleted, Prune does not complete the generation and RequeueAfter
+// returns requeueAfter so the controller polls until deletion is confirmed.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children, WithWaitForDeletion(5*time.Second))
+//	// ...
+//	return ctrl.Result{RequeueAfter: pruner.RequeueAfter()}, nil
+func WithWaitForDeletion(requeueAfter time.Duration) Option {
+	return func(p *Pruner) {
+		p.waitForDeletion = requeueAfter > 0
+		p.deletionPollInterval = requeueAfter
+	}
+}
+
+// WithOwnerReferenceCheck makes Prune verify, before deleting a child, that the
+// live object still carries an ownerReference to the owner.
+// Children failing the check are reported by Skipped instead of being deleted.
+// When combined with WithTrackingLabel, either marker is sufficient.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children, WithOwnerReferenceCheck(true))
+func WithOwnerReferenceCheck(enabled bool) Option {
+	return func(p *Pruner) {
+		p.checkOwnerRef = enabled
+	}
+}
+
+// WithTrackingLabel makes Prune verify, before deleting a child, that the live
+// object still carries the given label with the given value.
+// Children failing the check are reported by Skipped instead of being deleted.
+// When combined with WithOwnerReferenceCheck, either marker is sufficient.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children,
+//	    WithTrackingLabel("app.kubernetes.io/managed-by", "my-controller"),
+//	)
+func WithTrackingLabel(key, value string) Option {
+	return func(p *Pruner) {
+		p.trackingLabelKey = key
+		p.trackingLabelValue = value
+	}
+}
+
+// WithFreshOwnerReader makes Prune and PruneAll re-read the owner through
+// reader, typically the manager's uncached APIReader, before deleting anything.
+// When the stored owner has another generation, or another inventory than the
+// one the Pruner was created with, they return an error matching ErrStaleOwner.
+// Inventories kept in an annotation, a ConfigMap or a Secret are re-read
+// through reader as well.
+//
+// Default: the owner is trusted as passed to the constructor.
+//
+// Example:
+//
+//	pruner := NewPruner(client, owner, &owner.Status.Children,
+//	    WithFreshOwnerReader(mgr.GetAPIReader()),
+//	)
+func WithFreshOwnerReader(reader client.Reader) Option {
+	return func(p *Pruner) {
+		p.freshOwnerReader = reader
+	}
+}
+
 // defaultErrorHandler aggregates errors and returns them at the end.
 func defaultErrorHandler(ctx context.Context, err error, obj client.Object) error {
 	// Return the error to aggregate it
@@ -0,0 +1,108 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DeletionOrder groups kinds into waves that are deleted one after the other.
+// A wave only starts once every child of the previous waves is gone from the
+// cluster; until then the remaining children stay in the inventory and are
+// handled by a later reconcile. Children of an earlier wave that are still
+// terminating are kept in the Deleting state and Prune asks for a requeue, see
+// RequeueAfter; children of the later waves are kept in the PendingPrune state
+// until then. Kinds that are not listed belong to the first wave.
+type DeletionOrder [][]schema.GroupKind
+
+// defaultDeletionPollInterval is the requeue delay returned while a deletion
+// wave is terminating and WithWaitForDeletion sets no interval.
+const defaultDeletionPollInterval = 5 * time.Second
+
+// DefaultDeletionOrder mirrors the uninstall order of Helm and kapp: workloads
+// and traffic routing first, then services and configuration, then RBAC, and
+// finally API extensions and namespaces.
+var DefaultDeletionOrder = DeletionOrder{
+	{
+		{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"},
+		{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"},
+		{Group: "apiregistration.k8s.io", Kind: "APIService"},
+		{Group: "networking.k8s.io", Kind: "Ingress"},
+		{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"},
+		{Group: "batch", Kind: "CronJob"},
+		{Group: "batch", Kind: "Job"},
+		{Group: "apps", Kind: "StatefulSet"},
+		{Group: "apps", Kind: "Deployment"},
+		{Group: "apps", Kind: "ReplicaSet"},
+		{Group: "apps", Kind: "DaemonSet"},
+		{Group: "", Kind: "ReplicationController"},
+		{Group: "", Kind: "Pod"},
+	},
+	{
+		{Group: "", Kind: "Service"},
+		{Group: "", Kind: "Endpoints"},
+		{Group: "discovery.k8s.io", Kind: "EndpointSlice"},
+		{Group: "networking.k8s.io", Kind: "IngressClass"},
+		{Group: "networking.k8s.io", Kind: "NetworkPolicy"},
+		{Group: "policy", Kind: "PodDisruptionBudget"},
+		{Group: "", Kind: "ConfigMap"},
+		{Group: "", Kind: "Secret"},
+		{Group: "", Kind: "PersistentVolumeClaim"},
+		{Group: "", Kind: "PersistentVolume"},
+		{Group: "storage.k8s.io", Kind: "StorageClass"},
+		{Group: "", Kind: "LimitRange"},
+		{Group: "", Kind: "ResourceQuota"},
+	},
+	{
+		{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"},
+		{Group: "rbac.authorization.k8s.io", Kind: "Role"},
+		{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+		{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+		{Group: "", Kind: "ServiceAccount"},
+	},
+	{
+		{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+		{Group: "", Kind: "Namespace"},
+	},
+}
+
+// waves splits children into deletion waves, preserving their relative order
+// within a wave. Empty waves are omitted.
+func (o DeletionOrder) waves(children []ManagedChild) [][]ManagedChild {
+	waveOf := make(map[schema.GroupKind]int)
+	for i, kinds := range o {
+		for _, gk := range kinds {
+			if _, seen := waveOf[gk]; !seen {
+				waveOf[gk] = i
+			}
+		}
+	}
+
+	grouped := make([][]ManagedChild, max(len(o), 1))
+	for _, child := range children {
+		i := waveOf[child.Identity().GroupKind()]
+		grouped[i] = append(grouped[i], child)
+	}
+
+	waves := make([][]ManagedChild, 0, len(grouped))
+	for _, wave := range grouped {
+		if len(wave) > 0 {
+			waves = append(waves, wave)
+		}
+	}
+	return waves
+}
@@ -0,0 +1,255 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestDeletionOrder_Waves(t *testing.T) {
+	child := func(apiVersion, kind, name string) ManagedChild {
+		return ManagedChild{ObjectReference: corev1.ObjectReference{APIVersion: apiVersion, Kind: kind, Name: name}}
+	}
+	children := []ManagedChild{
+		child("v1", "Namespace", "ns"),
+		child("rbac.authorization.k8s.io/v1", "Role", "role"),
+		child("v1", "Service", "svc"),
+		child("example.com/v1", "Widget", "widget"),
+		child("apps/v1", "Deployment", "dep"),
+	}
+
+	waves := DefaultDeletionOrder.waves(children)
+
+	want := [][]string{{"widget", "dep"}, {"svc"}, {"role"}, {"ns"}}
+	if len(waves) != len(want) {
+		t.Fatalf("Expected %d waves, got %d", len(want), len(waves))
+	}
+	for i := range want {
+		if len(waves[i]) != len(want[i]) {
+			t.Fatalf("Wave %d: expected %v, got %+v", i, want[i], waves[i])
+		}
+		for j, name := range want[i] {
+			if got := waves[i][j].ObjectReference.Name; got != name {
+				t.Errorf("Wave %d position %d: expected %s, got %s", i, j, name, got)
+			}
+		}
+	}
+
+	if got := DeletionOrder(nil).waves(children); len(got) != 1 || len(got[0]) != len(children) {
+		t.Errorf("Expected a single wave without ordering, got %d waves", len(got))
+	}
+}
+
+func TestPruner_HoldsLaterWavesOnFailure(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+
+	failDeployments := true
+	var deleted []string
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				if failDeployments && obj.GetObjectKind().GroupVersionKind().Kind == "Deployment" {
+					return errors.New("delete refused")
+				}
+				deleted = append(deleted, obj.GetName())
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	owner := newTestOwner(1)
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", UID: "test-namespace-uid"},
+	}
+	deployment := newTestDeployment("test-deployment")
+	for _, obj := range []client.Object{namespace, deployment} {
+		if err := cl.Create(ctx, obj); err != nil {
+			t.Fatalf("Failed to create child: %v", err)
+		}
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	for _, obj := range []client.Object{namespace, deployment} {
+		if err := pruner.MarkReconciled(obj); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Generation 2 drops both children; the workload wave fails
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if _, err := pruner2.Prune(ctx); err == nil {
+		t.Fatalf("Expected Prune to fail")
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("Namespace must not be deleted before the workloads, deleted %v", deleted)
+	}
+	if len(owner.Status.Inventory.Children) != 2 {
+		t.Fatalf("Expected both children to stay in the inventory, got %d", len(owner.Status.Inventory.Children))
+	}
+
+	// The next reconcile completes both waves in order
+	failDeployments = false
+	pruner3 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if _, err := pruner3.Prune(ctx); err != nil {
+		t.Fatalf("Retried Prune failed: %v", err)
+	}
+	if len(deleted) != 2 || deleted[0] != deployment.Name || deleted[1] != namespace.Name {
+		t.Errorf("Expected deployment then namespace to be deleted, got %v", deleted)
+	}
+	if len(owner.Status.Inventory.Children) != 0 {
+		t.Errorf("Expected empty inventory, got %d children", len(owner.Status.Inventory.Children))
+	}
+}
+
+func TestPruner_HoldsLaterWavesWhileTerminating(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", UID: "test-namespace-uid"},
+	}
+	deployment := newTestDeployment("test-deployment")
+	deployment.Finalizers = []string{"example.com/slow"}
+	for _, obj := range []client.Object{namespace, deployment} {
+		if err := cl.Create(ctx, obj); err != nil {
+			t.Fatalf("Failed to create child: %v", err)
+		}
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	for _, obj := range []client.Object{namespace, deployment} {
+		if err := pruner.MarkReconciled(obj); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Generation 2 drops both children; the deployment stays Terminating
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if _, err := pruner2.Prune(ctx); err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(namespace), &corev1.Namespace{}); err != nil {
+		t.Fatalf("Namespace must not be deleted while the workloads terminate: %v", err)
+	}
+	if pruner2.RequeueAfter() != defaultDeletionPollInterval {
+		t.Errorf("Expected a requeue hint of %v, got %v", defaultDeletionPollInterval, pruner2.RequeueAfter())
+	}
+	children := owner.Status.Inventory.Children
+	if len(children) != 2 || children[1].State != ChildStateDeleting {
+		t.Fatalf("Expected the deployment to stay in the Deleting state, got %+v", children)
+	}
+
+	// The finalizer completes; the next reconcile deletes the namespace
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("Failed to get deployment: %v", err)
+	}
+	deployment.Finalizers = nil
+	if err := cl.Update(ctx, deployment); err != nil {
+		t.Fatalf("Failed to remove deployment finalizer: %v", err)
+	}
+
+	pruner3 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if _, err := pruner3.Prune(ctx); err != nil {
+		t.Fatalf("Third Prune failed: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(namespace), &corev1.Namespace{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the namespace to be deleted, got %v", err)
+	}
+	if len(owner.Status.Inventory.Children) != 0 {
+		t.Errorf("Expected empty inventory, got %+v", owner.Status.Inventory.Children)
+	}
+}
+
+func TestPruner_HeldWaveWithoutCommitMarker(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", UID: "test-namespace-uid"},
+	}
+	stale := newTestDeployment("stale")
+	stale.Finalizers = []string{"example.com/slow"}
+	kept := newTestDeployment("kept")
+	for _, obj := range []client.Object{namespace, stale, kept} {
+		if err := cl.Create(ctx, obj); err != nil {
+			t.Fatalf("Failed to create child: %v", err)
+		}
+	}
+
+	pruner := NewPruner(cl, owner, &owner.Status.Children, WithScheme(scheme))
+	for _, obj := range []client.Object{namespace, stale, kept} {
+		if err := pruner.MarkReconciled(obj); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Generation 2 only keeps one deployment; the stale one stays Terminating
+	owner.SetGeneration(2)
+	pruner2 := NewPruner(cl, owner, &owner.Status.Children, WithScheme(scheme))
+	if _, err := markAndPrune(t, pruner2, kept); err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if i := owner.Status.Children.Index(IdentityFromReference(corev1.ObjectReference{
+		APIVersion: "v1", Kind: "Namespace", Name: namespace.Name,
+	})); i < 0 || owner.Status.Children[i].State != ChildStatePendingPrune {
+		t.Fatalf("Expected the namespace to be held back, got %+v", owner.Status.Children)
+	}
+
+	// The finalizer completes; a reconcile at the same generation finishes the job
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(stale), stale); err != nil {
+		t.Fatalf("Failed to get deployment: %v", err)
+	}
+	stale.Finalizers = nil
+	if err := cl.Update(ctx, stale); err != nil {
+		t.Fatalf("Failed to remove deployment finalizer: %v", err)
+	}
+
+	pruner3 := NewPruner(cl, owner, &owner.Status.Children, WithScheme(scheme))
+	if _, err := markAndPrune(t, pruner3, kept); err != nil {
+		t.Fatalf("Third Prune failed: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(namespace), &corev1.Namespace{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the namespace to be deleted, got %v", err)
+	}
+	if children := owner.Status.Children; len(children) != 1 || children[0].ObjectReference.Name != kept.Name {
+		t.Errorf("Expected only the kept deployment to remain, got %+v", children)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Persist writes the inventory to the owner's status. It sends a merge patch
+// of the inventory field only, guarded by the owner's resourceVersion. On a
+// conflict, it re-reads the owner, re-applies the children this session
+// added, updated and removed to the latest inventory, and retries.
+//
+// Other status changes made by the caller are not written: update them
+// separately. After a Persist without conflict, the owner's resourceVersion
+// is advanced so that such an update does not conflict with Persist itself.
+//
+// The inventory must be a field of the owner, as with NewPruner and
+// NewInventoryPruner. For pruners created with NewPrunerWithStore on another
+// store, Persist is equivalent to Save.
+//
+// Example:
+//
+//	if _, err := pruner.Prune(ctx); err != nil {
+//	    return ctrl.Result{}, err
+//	}
+//	if err := pruner.Persist(ctx); err != nil {
+//	    return ctrl.Result{}, err
+//	}
+func (p *Pruner) Persist(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.persist(ctx)
+}
+
+// persist implements Persist; the caller must hold p.mu.
+func (p *Pruner) persist(ctx context.Context) error {
+	if !p.inventoryInOwner() {
+		if err := p.store.Save(ctx, p.inventory); err != nil {
+			return fmt.Errorf("failed to save inventory: %w", err)
+		}
+		p.baseInventory = *p.inventory.DeepCopy()
+		return nil
+	}
+
+	fields, err := p.inventoryFields()
+	if err != nil {
+		return err
+	}
+	delta := p.inventoryDelta()
+	if delta.empty() {
+		return nil
+	}
+
+	// The first attempt patches the session's changes onto the owner as read
+	base := p.owner.DeepCopyObject().(client.Object)
+	fields.set(base, p.baseInventory)
+	modified := p.owner.DeepCopyObject().(client.Object)
+
+	attempts := 0
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if attempts > 0 {
+			// Re-apply the session's changes onto the latest inventory
+			latest := p.owner.DeepCopyObject().(client.Object)
+			if err := p.client.Get(ctx, client.ObjectKeyFromObject(p.owner), latest); err != nil {
+				return err
+			}
+			base = latest.DeepCopyObject().(client.Object)
+			fields.set(latest, delta.applyTo(fields.get(latest)))
+			modified = latest
+		}
+		attempts++
+
+		patch := client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})
+		return p.client.Status().Patch(ctx, modified, patch)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist inventory: %w", err)
+	}
+
+	// The persisted inventory is the baseline of any later Persist
+	persisted := fields.get(modified)
+	if p.inventory != nil {
+		*p.inventory = *persisted.DeepCopy()
+	} else {
+		*p.statusChildren = slices.Clone(persisted.Children)
+	}
+	p.baseInventory = persisted
+	if attempts == 1 {
+		p.owner.SetResourceVersion(modified.GetResourceVersion())
+	}
+
+	return nil
+}
+
+// inventoryInOwner reports whether the inventory is kept in the owner rather
+// than in a separate InventoryStore.
+func (p *Pruner) inventoryInOwner() bool {
+	_, inStatus := p.store.(*statusStore)
+	return p.store == nil || inStatus
+}
+
+// currentInventory returns a copy of the inventory as modified in the session.
+func (p *Pruner) currentInventory() Inventory {
+	if p.inventory != nil {
+		return *p.inventory.DeepCopy()
+	}
+	return Inventory{Children: slices.Clone(*p.statusChildren)}
+}
+
+// inventoryDelta holds the inventory changes made during a session.
+type inventoryDelta struct {
+	upserted     []ManagedChild
+	removed      map[ChildIdentity]struct{}
+	completedGen *int64
+	fingerprint  *string
+}
+
+// inventoryDelta compares the inventory with its state at the start of the session.
+func (p *Pruner) inventoryDelta() inventoryDelta {
+	delta := inventoryDelta{removed: make(map[ChildIdentity]struct{})}
+	base, current := p.baseInventory, p.currentInventory()
+
+	for _, child := range current.Children {
+		if i := base.Children.Index(child.Identity()); i < 0 || base.Children[i] != child {
+			delta.upserted = append(delta.upserted, child)
+		}
+	}
+	for _, child := range base.Children {
+		if current.Children.Index(child.Identity()) < 0 {
+			delta.removed[child.Identity()] = struct{}{}
+		}
+	}
+	if current.CompletedGeneration != base.CompletedGeneration {
+		delta.completedGen = &current.CompletedGeneration
+	}
+	if current.Fingerprint != base.Fingerprint {
+		delta.fingerprint = &current.Fingerprint
+	}
+
+	return delta
+}
+
+// empty reports whether the session left the inventory unchanged.
+func (d inventoryDelta) empty() bool {
+	return len(d.upserted) == 0 && len(d.removed) == 0 && d.completedGen == nil && d.fingerprint == nil
+}
+
+// applyTo returns latest with the delta's children removed, updated or added.
+// The completed generation never moves backwards.
+func (d inventoryDelta) applyTo(latest Inventory) Inventory {
+	children := compactChildren(latest.Children)
+	merged := make(ManagedChildrenList, 0, len(children)+len(d.upserted))
+	for _, child := range children {
+		if _, removed := d.removed[child.Identity()]; !removed {
+			merged = append(merged, child)
+		}
+	}
+	for _, child := range d.upserted {
+		if i := merged.Index(child.Identity()); i >= 0 {
+			merged[i] = child
+		} else {
+			merged = append(merged, child)
+		}
+	}
+	latest.Children = merged
+
+	if d.completedGen != nil {
+		latest.CompletedGeneration = max(latest.CompletedGeneration, *d.completedGen)
+	}
+	if d.fingerprint != nil {
+		latest.Fingerprint = *d.fingerprint
+	}
+	return latest
+}
+
+// inventoryFields locates the inventory in copies of the owner: the whole
+// Inventory for inventory pruners, else the children list.
+type inventoryFields struct {
+	inventory []int
+	children  []int
+}
+
+// inventoryFields finds the field of the owner holding the inventory.
+func (p *Pruner) inventoryFields() (inventoryFields, error) {
+	var fields inventoryFields
+	var ok bool
+	if p.inventory != nil {
+		fields.inventory, ok = fieldPath(p.owner, p.inventory)
+	} else {
+		fields.children, ok = fieldPath(p.owner, p.statusChildren)
+	}
+	if !ok {
+		return fields, fmt.Errorf("the inventory is not a field of the owner %T, persist it with a status update instead", p.owner)
+	}
+	return fields, nil
+}
+
+// get returns a copy of the inventory held by obj, a copy of the owner.
+func (f inventoryFields) get(obj client.Object) Inventory {
+	v := reflect.ValueOf(obj).Elem()
+	if f.inventory != nil {
+		inventory := v.FieldByIndex(f.inventory).Interface().(Inventory)
+		return *inventory.DeepCopy()
+	}
+	children := v.FieldByIndex(f.children).Interface().(ManagedChildrenList)
+	return Inventory{Children: slices.Clone(children)}
+}
+
+// set replaces the inventory held by obj, a copy of the owner.
+func (f inventoryFields) set(obj client.Object, inventory Inventory) {
+	v := reflect.ValueOf(obj).Elem()
+	if f.inventory != nil {
+		v.FieldByIndex(f.inventory).Set(reflect.ValueOf(*inventory.DeepCopy()))
+		return
+	}
+	v.FieldByIndex(f.children).Set(reflect.ValueOf(slices.Clone(inventory.Children)))
+}
+
+// fieldPath returns the index path, within the struct obj points to, of the
+// exported field that ptr points to.
+func fieldPath(obj, ptr any) ([]int, bool) {
+	root := reflect.ValueOf(obj)
+	target := reflect.ValueOf(ptr)
+	if root.Kind() != reflect.Pointer || target.Kind() != reflect.Pointer {
+		return nil, false
+	}
+	return findField(root.Elem(), target.Pointer(), target.Type().Elem(), nil)
+}
+
+// findField walks the fields of v looking for the one at addr of type typ.
+func findField(v reflect.Value, addr uintptr, typ reflect.Type, path []int) ([]int, bool) {
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for i := range v.NumField() {
+		field := v.Field(i)
+		fieldPath := append(slices.Clone(path), i)
+		if field.Type() == typ && field.CanSet() && field.Addr().Pointer() == addr {
+			return fieldPath, true
+		}
+		if found, ok := findField(field, addr, typ, fieldPath); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// inventoryNames returns the names of the children in an inventory.
+func inventoryNames(children ManagedChildrenList) []string {
+	names := make([]string, 0, len(children))
+	for _, child := range children {
+		names = append(names, child.ObjectReference.Name)
+	}
+	return names
+}
+
+func TestPersist_PatchesInventory(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&TestCR{}).Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithFingerprint("v1"))
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if err := pruner.Persist(ctx); err != nil {
+		t.Fatalf("Persist failed: %v", err)
+	}
+
+	stored := &TestCR{}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(owner), stored); err != nil {
+		t.Fatalf("Failed to get owner: %v", err)
+	}
+	if names := inventoryNames(stored.Status.Inventory.Children); len(names) != 1 || names[0] != "test-deployment" {
+		t.Errorf("Expected the persisted inventory to hold the child, got %v", names)
+	}
+	if inventory := stored.Status.Inventory; inventory.CompletedGeneration != 1 || inventory.Fingerprint != "v1" {
+		t.Errorf("Expected generation 1 with fingerprint v1, got %d with %q", inventory.CompletedGeneration, inventory.Fingerprint)
+	}
+
+	// Persist advanced the owner's resourceVersion
+	if err := cl.Status().Update(ctx, owner); err != nil {
+		t.Errorf("Expected a status update after Persist to succeed: %v", err)
+	}
+}
+
+func TestPersist_MergesOnConflict(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	patches := 0
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&TestCR{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				patches++
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+	kept, stale, added := newTestDeployment("kept"), newTestDeployment("stale"), newTestDeployment("added")
+	for _, dep := range []client.Object{kept, stale, added} {
+		if err := cl.Create(ctx, dep); err != nil {
+			t.Fatalf("Failed to create deployment: %v", err)
+		}
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	for _, dep := range []client.Object{kept, stale} {
+		if err := pruner.MarkReconciled(dep); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if err := pruner.Persist(ctx); err != nil {
+		t.Fatalf("First Persist failed: %v", err)
+	}
+
+	// Generation 2 drops "stale" and adds "added"
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	for _, dep := range []client.Object{kept, added} {
+		if err := pruner2.MarkReconciled(dep); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner2.Prune(ctx); err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	// Another writer updates the inventory in the meantime
+	concurrent := &TestCR{}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(owner), concurrent); err != nil {
+		t.Fatalf("Failed to get owner: %v", err)
+	}
+	concurrent.Status.Inventory.Children = append(concurrent.Status.Inventory.Children, ManagedChild{
+		ObjectReference:    corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "concurrent"},
+		ObservedGeneration: 1,
+	})
+	if err := cl.Status().Update(ctx, concurrent); err != nil {
+		t.Fatalf("Failed to update owner status: %v", err)
+	}
+
+	patches = 0
+	if err := pruner2.Persist(ctx); err != nil {
+		t.Fatalf("Second Persist failed: %v", err)
+	}
+	if patches != 2 {
+		t.Errorf("Expected a conflict and a retry, got %d patches", patches)
+	}
+
+	stored := &TestCR{}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(owner), stored); err != nil {
+		t.Fatalf("Failed to get owner: %v", err)
+	}
+	inventory := stored.Status.Inventory
+	names := inventoryNames(inventory.Children)
+	want := []string{"kept", "concurrent", "added"}
+	if len(names) != len(want) {
+		t.Fatalf("Expected inventory %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("Expected inventory %v, got %v", want, names)
+		}
+	}
+	if gen := inventory.Children[0].ObservedGeneration; gen != 2 {
+		t.Errorf("Expected the kept child to be updated to generation 2, got %d", gen)
+	}
+	if inventory.CompletedGeneration != 2 {
+		t.Errorf("Expected completed generation 2, got %d", inventory.CompletedGeneration)
+	}
+	if got := inventoryNames(owner.Status.Inventory.Children); len(got) != len(want) {
+		t.Errorf("Expected the in-memory inventory to match the persisted one, got %v", got)
+	}
+}
+
+func TestPersist_RequiresInventoryInOwner(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&TestCR{}).Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+
+	var children ManagedChildrenList
+	pruner := NewPruner(cl, owner, &children, WithScheme(scheme))
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if err := pruner.Persist(ctx); err == nil {
+		t.Fatal("Expected Persist to fail for an inventory outside of the owner")
+	}
+}
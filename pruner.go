@@ -18,29 +18,83 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/tools/reference"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
+// ErrPruneStarted is returned by MarkReconciled, MarkReconciledRef, Apply and
+// RecordIntent once Prune or PruneAll has started: the session's desired set is
+// sealed, and a child marked that late could already have been deleted. Create
+// a new Pruner for the next reconcile.
+var ErrPruneStarted = errors.New("prune already started in this session")
+
 // Pruner manages reconciliation and pruning of child resources.
 // Create a new instance for each reconciliation session using NewPruner.
 type Pruner struct {
-	client       client.Client
-	scheme       *runtime.Scheme
-	deleteOpts   []client.DeleteOption
-	errorHandler ErrorHandlerFunc
+	client         client.Client
+	scheme         *runtime.Scheme
+	fieldOwner     string
+	dryRun         bool
+	deleteOpts     []client.DeleteOption
+	errorHandler   ErrorHandlerFunc
+	deletionOrder  DeletionOrder
+	concurrency    int
+	pruneMode      PruneMode
+	fingerprint    string
+	epochSource    EpochSource
+	limits         *PruneLimits
+	recorder       record.EventRecorder
+	metrics        bool
+	log            *logr.Logger
+	tracerProvider trace.TracerProvider
+	rules          map[schema.GroupKind]PruneRule
+
+	// Wait-for-gone mode
+	waitForDeletion      bool
+	deletionPollInterval time.Duration
+
+	// Ownership verification before deletion
+	checkOwnerRef      bool
+	trackingLabelKey   string
+	trackingLabelValue string
 
-	// Reconciliation state
+	// Owner freshness verification before pruning
+	freshOwnerReader client.Reader
+
+	// Reconciliation state, guarded by mu
+	mu             sync.Mutex
+	sealed         bool           // set once Prune or PruneAll starts; later marks are refused
+	applies        sync.WaitGroup // Apply calls admitted before the seal, awaited by Prune
 	owner          client.Object
+	generation     int64  // owner generation, or epoch, of the session
+	epochKey       string // spec hash or resourceVersion, compared instead of a generation
+	generationErr  error
+	store          InventoryStore
+	inventory      *Inventory
 	statusChildren *ManagedChildrenList
-	desiredRefs    map[corev1.ObjectReference]struct{}
+	completedGen   *int64
+	baseInventory  Inventory // inventory at the start of the session, for Persist
+	desiredRefs    map[ChildIdentity]struct{}
+	added          map[ChildIdentity]struct{} // children absent from the inventory before the session
+	applyErrors    map[ChildIdentity]error
+	intents        map[ChildIdentity]struct{}
 	pruned         []corev1.ObjectReference
+	skipped        []SkippedChild
 	lastAppliedGen int64
+	requeueAfter   time.Duration
 }
 
 // NewPruner creates a new Pruner instance for a reconciliation session.
@@ -59,22 +113,83 @@ type Pruner struct {
 //	    reconcileprune.WithScheme(r.Scheme),
 //	)
 func NewPruner(c client.Client, owner client.Object, statusChildren *ManagedChildrenList, opts ...Option) *Pruner {
+	p := newPruner(c, owner, statusChildren, opts)
+
+	// Capture the last applied generation BEFORE any modifications
+	p.lastAppliedGen = getLastAppliedGeneration(*statusChildren, p.generation)
+
+	return p
+}
+
+// NewInventoryPruner creates a new Pruner instance backed by an Inventory.
+// Unlike NewPruner, the decision to prune is driven by the inventory's
+// CompletedGeneration, which Prune only advances after it succeeds. A
+// reconcile that fails between MarkReconciled and Prune is therefore retried
+// with the same pruning decision instead of leaking the previous generation.
+//
+// Example:
+//
+//	pruner := reconcileprune.NewInventoryPruner(r.Client, &myCR, &myCR.Status.Inventory,
+//	    reconcileprune.WithScheme(r.Scheme),
+//	)
+func NewInventoryPruner(c client.Client, owner client.Object, inventory *Inventory, opts ...Option) *Pruner {
+	return newInventoryPruner(c, owner, inventory, NewStatusStore(inventory), opts)
+}
+
+// NewPrunerWithStore creates a new Pruner instance whose inventory is loaded
+// from store. Call Save after Prune to write the inventory back.
+// It behaves like NewInventoryPruner otherwise.
+//
+// Example:
+//
+//	store := reconcileprune.NewConfigMapStore(r.Client, &myCR, "")
+//	pruner, err := reconcileprune.NewPrunerWithStore(ctx, r.Client, &myCR, store,
+//	    reconcileprune.WithScheme(r.Scheme),
+//	)
+func NewPrunerWithStore(ctx context.Context, c client.Client, owner client.Object, store InventoryStore, opts ...Option) (*Pruner, error) {
+	inventory, err := store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load inventory: %w", err)
+	}
+	return newInventoryPruner(c, owner, inventory, store, opts), nil
+}
+
+// newInventoryPruner builds a Pruner around an inventory and its store.
+func newInventoryPruner(c client.Client, owner client.Object, inventory *Inventory, store InventoryStore, opts []Option) *Pruner {
+	p := newPruner(c, owner, &inventory.Children, opts)
+	p.store = store
+	p.inventory = inventory
+	p.completedGen = &inventory.CompletedGeneration
+	p.baseInventory = *inventory.DeepCopy()
+	p.lastAppliedGen = inventory.CompletedGeneration
+
+	return p
+}
+
+// newPruner builds a Pruner around the given children list and applies opts.
+func newPruner(c client.Client, owner client.Object, statusChildren *ManagedChildrenList, opts []Option) *Pruner {
 	p := &Pruner{
 		client:         c,
 		errorHandler:   defaultErrorHandler,
+		deletionOrder:  DefaultDeletionOrder,
 		owner:          owner,
 		statusChildren: statusChildren,
-		desiredRefs:    make(map[corev1.ObjectReference]struct{}),
+		desiredRefs:    make(map[ChildIdentity]struct{}),
+		added:          make(map[ChildIdentity]struct{}),
+		applyErrors:    make(map[ChildIdentity]error),
+		intents:        make(map[ChildIdentity]struct{}),
 		pruned:         []corev1.ObjectReference{},
 	}
 
-	// Capture the last applied generation BEFORE any modifications
-	currentGen := owner.GetGeneration()
-	p.lastAppliedGen = getLastAppliedGeneration(*statusChildren, currentGen)
+	// Collapse duplicate entries left behind by older versions, which keyed
+	// children on the full ObjectReference including its ResourceVersion
+	*statusChildren = compactChildren(*statusChildren)
+	p.baseInventory.Children = slices.Clone(*statusChildren)
 
 	for _, opt := range opts {
 		opt(p)
 	}
+	p.generation, p.epochKey, p.generationErr = p.sessionGeneration()
 
 	return p
 }
@@ -86,8 +201,17 @@ func NewPruner(c client.Client, owner client.Object, statusChildren *ManagedChil
 // The user is responsible for applying the resource using their preferred method
 // (e.g., SSA, Create/Update, or any other approach).
 //
-// Returns an error if the object reference cannot be generated or if the object
-// has not been created yet (missing UID).
+// Objects carrying their own GVK, such as unstructured.Unstructured or
+// metav1.PartialObjectMetadata, need no scheme registration. Typed objects
+// without TypeMeta are resolved through the scheme from WithScheme, or the
+// client's scheme.
+//
+// MarkReconciled is safe for concurrent use, so children can be applied and
+// marked from several goroutines.
+//
+// Returns an error if the object reference cannot be generated, if the object
+// has not been created yet (missing UID), or ErrPruneStarted once Prune has
+// started.
 func (p *Pruner) MarkReconciled(obj client.Object) error {
 	// Validate that the object has been created (has a UID)
 	if obj.GetUID() == "" {
@@ -96,129 +220,594 @@ func (p *Pruner) MarkReconciled(obj client.Object) error {
 	}
 
 	// Generate reference for this object
-	ref, err := reference.GetReference(p.scheme, obj)
+	gvk, err := p.gvkFor(obj)
 	if err != nil {
 		return fmt.Errorf("failed to generate reference for object: %w", err)
 	}
+	return p.markReconciled(corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+	})
+}
 
-	// Track as desired
-	p.desiredRefs[*ref] = struct{}{}
+// MarkReconciledRef marks a child as reconciled (desired) for this session
+// without needing the object itself, e.g. when the child was intentionally
+// left untouched this round or applied through a dynamic client.
+//
+// The uid guards the child's deletion against re-creation. When it is empty,
+// the UID recorded in the inventory for this child, if any, is kept.
+//
+// Example:
+//
+//	err := pruner.MarkReconciledRef(appsv1.SchemeGroupVersion.WithKind("Deployment"),
+//	    client.ObjectKey{Namespace: "default", Name: "my-app"}, "")
+func (p *Pruner) MarkReconciledRef(gvk schema.GroupVersionKind, key client.ObjectKey, uid types.UID) error {
+	if gvk.Kind == "" || key.Name == "" {
+		return fmt.Errorf("a kind and a name are required to mark %s %s as reconciled", gvk, key)
+	}
+	return p.markReconciled(corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  key.Namespace,
+		Name:       key.Name,
+		UID:        uid,
+	})
+}
 
-	// Update child tracking
-	currentGen := p.owner.GetGeneration()
-	p.upsertChild(p.statusChildren, *ref, currentGen)
+// markReconciled records a child reference as desired in the current
+// generation, unless the session is sealed.
+func (p *Pruner) markReconciled(ref corev1.ObjectReference) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
+	if p.sealed {
+		return fmt.Errorf("%w: %s cannot be marked as reconciled", ErrPruneStarted, IdentityFromReference(ref))
+	}
+	p.markDesired(ref)
 	return nil
 }
 
+// markDesired records a child reference as desired in the current generation;
+// the caller must hold p.mu.
+func (p *Pruner) markDesired(ref corev1.ObjectReference) {
+	id := IdentityFromReference(ref)
+	switch i := p.statusChildren.Index(id); {
+	case i < 0:
+		p.added[id] = struct{}{}
+	case ref.UID == "":
+		ref.UID = (*p.statusChildren)[i].ObjectReference.UID
+	}
+
+	// Track as desired, superseding an earlier failed Apply
+	p.desiredRefs[id] = struct{}{}
+	delete(p.applyErrors, id)
+
+	// Update child tracking
+	p.upsertChild(p.statusChildren, ref, p.generation)
+
+	p.logger(context.Background()).V(logLevelDebug).Info("Marked child as reconciled", childLogValues(ref)...)
+}
+
+// seal refuses later marks and waits for the Apply calls admitted before it,
+// so that Prune sees every child they apply.
+func (p *Pruner) seal() {
+	p.mu.Lock()
+	p.sealed = true
+	p.mu.Unlock()
+	p.applies.Wait()
+}
+
+// gvkFor returns the object's GVK, resolving it through the scheme when the
+// object has no TypeMeta.
+func (p *Pruner) gvkFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	if gvk := obj.GetObjectKind().GroupVersionKind(); !gvk.Empty() {
+		return gvk, nil
+	}
+	return apiutil.GVKForObject(obj, p.runtimeScheme())
+}
+
+// runtimeScheme returns the scheme from WithScheme, or the client's scheme.
+func (p *Pruner) runtimeScheme() *runtime.Scheme {
+	if p.scheme != nil {
+		return p.scheme
+	}
+	return p.client.Scheme()
+}
+
 // Prune removes stale resources that were not marked as reconciled in this session.
 // Must be called after all MarkReconciled() calls.
 // This method prunes resources from previous generations that are no longer desired.
+// WithPruneMode and WithFingerprint select other triggers than a generation change.
 //
 // The children slice is modified in-place. After Prune() returns successfully,
 // you should update the owner's status subresource to persist the changes.
+// When the Pruner was created with NewInventoryPruner, a successful Prune also
+// records the current generation as the inventory's CompletedGeneration, and
+// the fingerprint with PruneOnFingerprintChange, unless an Apply call failed
+// in this session.
 //
 // Parameters:
 //   - ctx: Context for the operation
 //
 // Returns:
-//   - List of pruned resources as ObjectReferences
+//   - PruneResult with the outcome of every inventory child, also on error
 //   - error if the pruning operation fails
 //
 // Example:
 //
-//	pruned, err := pruner.Prune(ctx)
+//	result, err := pruner.Prune(ctx)
 //	if err != nil {
 //	    return ctrl.Result{}, err
 //	}
+//	log.Info("pruned children", "deleted", result.Counts[OutcomeDeleted])
 //	// Update status to persist changes
 //	if err := r.Status().Update(ctx, &myCR); err != nil {
 //	    return ctrl.Result{}, err
 //	}
-func (p *Pruner) Prune(ctx context.Context) ([]corev1.ObjectReference, error) {
+func (p *Pruner) Prune(ctx context.Context) (result *PruneResult, err error) {
+	// Wait for in-flight MarkReconciled and Apply calls, and refuse later ones
+	p.seal()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	start := time.Now()
+	ctx, span := p.startPruneSpan(ctx)
+	defer func() { endPruneSpan(span, result, err) }()
+
 	// Get current generation
-	currentGen := p.owner.GetGeneration()
+	currentGen := p.generation
+
+	if p.generationErr != nil {
+		return newPruneResult(nil, p.pruned, 0), p.generationErr
+	}
+	if p.tracksFingerprint() && p.inventory == nil {
+		return newPruneResult(nil, p.pruned, 0), errFingerprintWithoutInventory
+	}
+	if err := p.checkFreshOwner(ctx); err != nil {
+		return newPruneResult(nil, p.pruned, 0), err
+	}
 
 	// Prune resources from previous generation that are no longer desired
-	// Only prune if the spec has changed (currentGen > lastAppliedGen captured in constructor)
-	if currentGen > p.lastAppliedGen {
-		pruneErrors := p.pruneStaleResources(ctx, p.statusChildren, p.desiredRefs, p.lastAppliedGen)
-		if len(pruneErrors) > 0 {
-			return p.pruned, errors.Join(pruneErrors...)
-		}
+	// Only prune if the spec has changed (currentGen > lastAppliedGen captured in constructor),
+	// or as the prune mode dictates, but always follow up on children whose
+	// deletion is still in progress
+	pruneGeneration := p.shouldPrune(currentGen)
+	inventory := slices.Clone(*p.statusChildren)
+	children, pruneErrors := p.pruneStaleResources(ctx, p.statusChildren, p.desiredRefs, p.lastAppliedGen, pruneGeneration)
+	pruneErr := errors.Join(pruneErrors...)
+
+	// Deletions are not confirmed yet: ask for a requeue and keep the generation open
+	deleting := pruneErr == nil && hasDeletingChildren(*p.statusChildren)
+	if deleting {
+		p.requeueAfter = p.pollInterval()
+	}
+
+	result = newPruneResult(children, p.pruned, p.requeueAfter)
+	p.logDecisions(ctx, inventory, result)
+	p.recordEvents(result, pruneErr)
+	p.recordMetrics(result, time.Since(start), errors.Is(pruneErr, ErrPruneThresholdExceeded))
+	if pruneErr != nil {
+		return result, pruneErr
+	}
+	if deleting || len(p.applyErrors) > 0 {
+		return result, nil
 	}
 
-	return p.pruned, nil
+	// Commit the generation only once every child has been applied and every
+	// stale child has been handled
+	if p.completedGen != nil && currentGen > *p.completedGen {
+		*p.completedGen = currentGen
+	}
+	if p.tracksFingerprint() {
+		p.inventory.Fingerprint = p.sessionFingerprint()
+	}
+
+	return result, nil
+}
+
+// Save writes the inventory through the pruner's InventoryStore.
+// Call it after Prune when using NewPrunerWithStore. For pruners created with
+// NewPruner or NewInventoryPruner it is a no-op: persist the owner's status
+// instead, for example with Persist.
+func (p *Pruner) Save(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.store == nil {
+		return nil
+	}
+	if err := p.store.Save(ctx, p.inventory); err != nil {
+		return fmt.Errorf("failed to save inventory: %w", err)
+	}
+	return nil
+}
+
+// RequeueAfter returns how long the controller should wait before reconciling
+// again to confirm deletions still in progress. It is zero unless Prune left
+// children in the Deleting state, either with WithWaitForDeletion or because a
+// deletion wave must be gone before the next one starts.
+func (p *Pruner) RequeueAfter() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requeueAfter
+}
+
+// pollInterval returns the requeue delay used while deletions are in progress.
+func (p *Pruner) pollInterval() time.Duration {
+	if p.deletionPollInterval > 0 {
+		return p.deletionPollInterval
+	}
+	return defaultDeletionPollInterval
+}
+
+// Skipped returns the children that Prune left in place, along with the reason.
+// Skipped children are removed from the inventory since they are no longer
+// considered managed by the owner.
+func (p *Pruner) Skipped() []SkippedChild {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.skipped
 }
 
 // pruneStaleResources deletes resources from previous generations that are no longer desired.
 func (p *Pruner) pruneStaleResources(
 	ctx context.Context,
 	statusChildren *ManagedChildrenList,
-	desiredRefs map[corev1.ObjectReference]struct{},
+	desiredRefs map[ChildIdentity]struct{},
 	lastAppliedGen int64,
-) []error {
-	var pruneErrors []error
-	newChildren := ManagedChildrenList{}
+	pruneGeneration bool,
+) ([]ChildResult, []error) {
+	children := *statusChildren
+	results := make([]ChildResult, len(children))
+	index := make(map[ChildIdentity]int, len(children))
+
+	var stale, candidates []ManagedChild
+	for i, child := range children {
+		index[child.Identity()] = i
 
-	for _, child := range *statusChildren {
 		// Keep if it's in the desired set
-		if _, desired := desiredRefs[child.ObjectReference]; desired {
-			newChildren = append(newChildren, child)
+		if _, desired := desiredRefs[child.Identity()]; desired {
+			results[i] = keptResult(child, ReasonDesired)
 			continue
 		}
 
-		// Keep if it's from the current generation (just applied)
-		if child.ObservedGeneration > lastAppliedGen {
-			newChildren = append(newChildren, child)
+		// Keep children whose apply failed: they are still wanted
+		if _, failed := p.applyErrors[child.Identity()]; failed {
+			results[i] = keptResult(child, ReasonApplyFailed)
+			continue
+		}
+
+		// Keep intents recorded in this session whose apply is not confirmed
+		if _, recorded := p.intents[child.Identity()]; recorded && child.State == ChildStatePending {
+			results[i] = keptResult(child, ReasonIntentPending)
+			continue
+		}
+
+		// Follow up on children whose deletion is in progress or was held
+		// back by an earlier wave
+		if child.State == ChildStateDeleting || child.State == ChildStatePendingPrune {
+			stale = append(stale, child)
+			continue
+		}
+
+		// Keep everything else if the generation is not being pruned
+		if !pruneGeneration {
+			results[i] = keptResult(child, p.unchangedReason())
+			continue
+		}
+
+		// Keep if it's from the current generation (just applied), unless
+		// every child not marked in this session is pruned. Pending intents
+		// left by an earlier session were never applied.
+		if p.pruneMode != PruneAlways && child.State != ChildStatePending && child.ObservedGeneration > lastAppliedGen {
+			results[i] = keptResult(child, ReasonCurrentGeneration)
 			continue
 		}
 
 		// This child is from a previous generation and not desired - prune it
-		obj := &unstructured.Unstructured{}
-		obj.SetAPIVersion(child.ObjectReference.APIVersion)
-		obj.SetKind(child.ObjectReference.Kind)
-		obj.SetName(child.ObjectReference.Name)
-		obj.SetNamespace(child.ObjectReference.Namespace)
-
-		if err := p.deleteResource(ctx, obj); err != nil {
-			// Call error handler
-			handledErr := p.errorHandler(ctx, err, obj)
-			if handledErr != nil {
-				pruneErrors = append(pruneErrors, handledErr)
-				// Keep the child in status if deletion failed
-				newChildren = append(newChildren, child)
-			} else {
-				// Error was ignored by handler, record as pruned
-				p.pruned = append(p.pruned, child.ObjectReference)
+		stale = append(stale, child)
+		candidates = append(candidates, child)
+	}
+
+	// Refuse mass deletions before touching anything, measuring them against
+	// the inventory as it was before the session
+	total := 0
+	for _, child := range children {
+		if _, added := p.added[child.Identity()]; !added {
+			total++
+		}
+	}
+	if err := p.checkPruneLimits(candidates, total); err != nil {
+		for _, child := range stale {
+			results[index[child.Identity()]] = keptResult(child, ReasonThresholdExceeded)
+		}
+		return results, []error{err}
+	}
+
+	var pruneErrors []error
+	removed := make(map[ChildIdentity]struct{})
+	states := make(map[ChildIdentity]ChildState)
+	deleting := false
+
+	waves := p.deletionOrder.waves(stale)
+	for w, wave := range waves {
+		// A wave only starts once the previous ones are complete; the
+		// remaining children stay in status, flagged so that the next
+		// reconcile prunes them even if the generation does not change
+		if len(pruneErrors) > 0 || deleting {
+			for _, child := range wave {
+				results[index[child.Identity()]] = keptResult(child, ReasonPreviousWaveIncomplete)
+				if child.State != ChildStateDeleting {
+					states[child.Identity()] = ChildStatePendingPrune
+				}
+			}
+			continue
+		}
+
+		// Children of a wave followed by another one must be gone, not only
+		// accepted for deletion, before the next wave starts
+		waitForGone := p.waitForDeletion || w < len(waves)-1
+		for i, result := range p.removeWave(ctx, wave, waitForGone, ReasonNotReconciled) {
+			id := wave[i].Identity()
+			results[index[id]] = result
+			switch result.Outcome {
+			case OutcomeFailed:
+				pruneErrors = append(pruneErrors, result.Error)
+			case OutcomeDeleting:
+				states[id] = ChildStateDeleting
+				deleting = true
+			default:
+				removed[id] = struct{}{}
 			}
-		} else {
-			p.pruned = append(p.pruned, child.ObjectReference)
 		}
 	}
 
-	*statusChildren = newChildren
-	return pruneErrors
+	if len(removed) > 0 || len(states) > 0 {
+		*statusChildren = updateChildren(children, removed, states)
+	}
+	return results, pruneErrors
+}
+
+// keptResult reports a child that was not a prune candidate.
+func keptResult(child ManagedChild, reason string) ChildResult {
+	return ChildResult{
+		ObjectReference: child.ObjectReference,
+		Outcome:         OutcomeKept,
+		Reason:          reason,
+		Timestamp:       time.Now(),
+	}
+}
+
+// removalAttempt holds what the API calls made for a single child returned.
+type removalAttempt struct {
+	obj         *unstructured.Unstructured
+	skipReason  string
+	alreadyGone bool
+	terminating bool
+	err         error
+}
+
+// removeWave removes the children of a wave, issuing up to p.concurrency
+// deletions in parallel. Outcomes are recorded sequentially in wave order, so
+// the error handler is never called concurrently and results are deterministic.
+// When waitForGone is set, a child is only considered done once it no longer
+// exists in the cluster. The reason is reported for children that were
+// deleted or failed to be.
+func (p *Pruner) removeWave(ctx context.Context, wave []ManagedChild, waitForGone bool, reason string) []ChildResult {
+	attempts := make([]removalAttempt, len(wave))
+
+	workers := min(max(p.concurrency, 1), len(wave))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				attempts[i] = p.attemptRemoval(ctx, wave[i], waitForGone)
+			}
+		}()
+	}
+	for i := range wave {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	results := make([]ChildResult, len(wave))
+	for i, child := range wave {
+		results[i] = p.recordRemoval(ctx, child, attempts[i], reason)
+	}
+	return results
+}
+
+// attemptRemoval performs the API calls needed to remove a single child.
+// It does not touch the pruner's state and is safe to call concurrently.
+func (p *Pruner) attemptRemoval(ctx context.Context, child ManagedChild, waitForGone bool) (attempt removalAttempt) {
+	ctx, span := p.startDeleteSpan(ctx, child.ObjectReference)
+	defer func() { endDeleteSpan(span, attempt) }()
+
+	attempt = removalAttempt{obj: childObject(child)}
+
+	if p.isInventoryObject(child.Identity()) {
+		attempt.skipReason = SkipReasonInventoryObject
+		return attempt
+	}
+	if p.ruleFor(child.Identity().GroupKind()).Protected {
+		attempt.skipReason = SkipReasonProtected
+		return attempt
+	}
+
+	attempt.skipReason, attempt.alreadyGone, attempt.err = p.deleteChild(ctx, child, attempt.obj)
+	if attempt.err == nil && attempt.skipReason == "" && !attempt.alreadyGone && waitForGone && !p.dryRun {
+		var gone bool
+		gone, attempt.err = p.isGone(ctx, child)
+		attempt.terminating = attempt.err == nil && !gone
+	}
+	return attempt
+}
+
+// isInventoryObject reports whether id is the ConfigMap or Secret holding the
+// Pruner's inventory.
+func (p *Pruner) isInventoryObject(id ChildIdentity) bool {
+	store, ok := p.store.(*objectStore)
+	return ok && id == store.identity()
+}
+
+// recordRemoval calls the error handler if needed, records the child as
+// pruned or skipped and reports its outcome.
+func (p *Pruner) recordRemoval(ctx context.Context, child ManagedChild, attempt removalAttempt, reason string) ChildResult {
+	result := ChildResult{
+		ObjectReference: child.ObjectReference,
+		Reason:          reason,
+		Timestamp:       time.Now(),
+	}
+
+	switch {
+	case attempt.terminating:
+		result.Outcome = OutcomeDeleting
+		result.Reason = ReasonDeletionPending
+		return result
+	case attempt.err != nil:
+		// Call error handler
+		handler := p.errorHandlerFor(child.Identity().GroupKind())
+		if handledErr := handler(ctx, attempt.err, attempt.obj); handledErr != nil {
+			result.Outcome = OutcomeFailed
+			result.Error = handledErr
+			return result
+		}
+		// Error was ignored by handler, record as pruned
+		result.Outcome = OutcomeIgnored
+		result.Error = attempt.err
+	case attempt.skipReason != "":
+		// The live object must be left alone, stop tracking it
+		p.skipped = append(p.skipped, SkippedChild{
+			ObjectReference: child.ObjectReference,
+			Reason:          attempt.skipReason,
+		})
+		result.Outcome = OutcomeSkipped
+		result.Reason = attempt.skipReason
+		return result
+	case attempt.alreadyGone && child.State != ChildStateDeleting:
+		result.Outcome = OutcomeAlreadyGone
+	case p.dryRun && !attempt.alreadyGone:
+		result.Outcome = OutcomeDryRun
+	default:
+		result.Outcome = OutcomeDeleted
+	}
+
+	p.pruned = append(p.pruned, child.ObjectReference)
+	return result
+}
+
+// updateChildren drops the removed children and sets the state of the deleting
+// and held back ones, preserving the order of the remaining children.
+func updateChildren(children ManagedChildrenList, removed map[ChildIdentity]struct{}, states map[ChildIdentity]ChildState) ManagedChildrenList {
+	kept := ManagedChildrenList{}
+	for _, child := range children {
+		id := child.Identity()
+		if _, ok := removed[id]; ok {
+			continue
+		}
+		if state, ok := states[id]; ok {
+			child.State = state
+		}
+		kept = append(kept, child)
+	}
+	return kept
+}
+
+// hasDeletingChildren reports whether some children are still being deleted.
+func hasDeletingChildren(children ManagedChildrenList) bool {
+	for _, child := range children {
+		if child.State == ChildStateDeleting {
+			return true
+		}
+	}
+	return false
 }
 
-// deleteResource deletes a resource, ignoring NotFound errors.
-func (p *Pruner) deleteResource(ctx context.Context, obj client.Object) error {
-	if err := p.client.Delete(ctx, obj, p.deleteOpts...); err != nil {
+// deleteChild deletes a stale child, ignoring NotFound errors.
+// The live object is inspected first: children that were re-created by someone
+// else, lost the owner's ownerReference or tracking label (when verification is
+// enabled), or opted out of pruning through an annotation are left in place.
+// The deletion itself is guarded by a UID precondition to close the race with
+// a concurrent re-creation. A non-empty skip reason is returned when the child
+// was left in place, and true when it no longer existed.
+func (p *Pruner) deleteChild(ctx context.Context, child ManagedChild, obj *unstructured.Unstructured) (string, bool, error) {
+	if err := p.client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
 		if apierrors.IsNotFound(err) {
-			return nil // Already deleted
+			return "", true, nil
 		}
-		return err
+		return "", false, err
 	}
-	return nil
+	if !p.isOwned(child, obj) {
+		return SkipReasonIdentityMismatch, false, nil
+	}
+	if pruneDisabled(obj) {
+		return SkipReasonAnnotation, false, nil
+	}
+
+	if err := p.client.Delete(ctx, obj, p.deleteOptionsFor(child)...); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", true, nil // Already deleted
+		}
+		if child.ObjectReference.UID != "" && apierrors.IsConflict(err) {
+			return SkipReasonIdentityMismatch, false, nil // UID precondition failed
+		}
+		return "", false, err
+	}
+	return "", false, nil
+}
+
+// verifiesOwnership reports whether live objects must be checked before deletion.
+func (p *Pruner) verifiesOwnership() bool {
+	return p.checkOwnerRef || p.trackingLabelKey != ""
+}
+
+// isOwned reports whether the live object is the child recorded in the
+// inventory and, when verification is enabled, still carries the owner's
+// ownerReference or tracking label.
+func (p *Pruner) isOwned(child ManagedChild, live client.Object) bool {
+	if uid := child.ObjectReference.UID; uid != "" && live.GetUID() != uid {
+		return false
+	}
+	if !p.verifiesOwnership() {
+		return true
+	}
+	if p.checkOwnerRef && hasOwnerReference(live, p.owner) {
+		return true
+	}
+	if p.trackingLabelKey != "" {
+		if value, ok := live.GetLabels()[p.trackingLabelKey]; ok && value == p.trackingLabelValue {
+			return true
+		}
+	}
+	return false
+}
+
+// childObject builds a minimal object addressing the given child.
+func childObject(child ManagedChild) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(child.ObjectReference.APIVersion)
+	obj.SetKind(child.ObjectReference.Kind)
+	obj.SetName(child.ObjectReference.Name)
+	obj.SetNamespace(child.ObjectReference.Namespace)
+	return obj
 }
 
 // upsertChild updates or adds a child to the children list.
+// Children are matched by identity; the stored reference is refreshed so that
+// the recorded UID and API version follow the latest applied object.
 func (p *Pruner) upsertChild(statusChildren *ManagedChildrenList, ref corev1.ObjectReference, observedGeneration int64) {
-	for i := range *statusChildren {
-		if (*statusChildren)[i].ObjectReference == ref {
-			(*statusChildren)[i].ObservedGeneration = observedGeneration
-			return
-		}
+	if i := statusChildren.Index(IdentityFromReference(ref)); i >= 0 {
+		(*statusChildren)[i].ObjectReference = ref
+		(*statusChildren)[i].ObservedGeneration = observedGeneration
+		(*statusChildren)[i].State = ""
+		return
 	}
 	*statusChildren = append(*statusChildren, ManagedChild{
 		ObjectReference:    ref,
@@ -226,7 +815,39 @@ func (p *Pruner) upsertChild(statusChildren *ManagedChildrenList, ref corev1.Obj
 	})
 }
 
+// sanitizeReference keeps only the fields needed to identify a child and to
+// guard its deletion. Volatile fields such as ResourceVersion change on every
+// update and must not be persisted in the inventory.
+func sanitizeReference(ref corev1.ObjectReference) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: ref.APIVersion,
+		Kind:       ref.Kind,
+		Namespace:  ref.Namespace,
+		Name:       ref.Name,
+		UID:        ref.UID,
+	}
+}
+
+// compactChildren sanitizes every reference and merges entries sharing the same
+// identity, keeping the one with the highest ObservedGeneration.
+func compactChildren(children ManagedChildrenList) ManagedChildrenList {
+	compacted := make(ManagedChildrenList, 0, len(children))
+	for _, child := range children {
+		child.ObjectReference = sanitizeReference(child.ObjectReference)
+		i := compacted.Index(child.Identity())
+		if i < 0 {
+			compacted = append(compacted, child)
+			continue
+		}
+		if child.ObservedGeneration > compacted[i].ObservedGeneration {
+			compacted[i] = child
+		}
+	}
+	return compacted
+}
+
 // getLastAppliedGeneration returns the maximum ObservedGeneration from children.
+// It is only used by NewPruner, which has no commit marker to rely on.
 // If all children have the current generation, returns currentGen.
 // Otherwise returns the highest generation found.
 func getLastAppliedGeneration(children ManagedChildrenList, currentGen int64) int64 {
@@ -236,6 +857,10 @@ func getLastAppliedGeneration(children ManagedChildrenList, currentGen int64) in
 
 	maxGen := int64(0)
 	for _, child := range children {
+		// Pending intents were never applied
+		if child.State == ChildStatePending {
+			continue
+		}
 		if child.ObservedGeneration > maxGen {
 			maxGen = child.ObservedGeneration
 		}
@@ -16,14 +16,23 @@ package reconcileprune
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 // TestCR is a minimal Custom Resource for testing
@@ -56,12 +65,14 @@ func (t *TestCR) DeepCopyInto(out *TestCR) {
 		out.Status.Children = make(ManagedChildrenList, len(t.Status.Children))
 		copy(out.Status.Children, t.Status.Children)
 	}
+	t.Status.Inventory.DeepCopyInto(&out.Status.Inventory)
 }
 
 type TestCRSpec struct{}
 
 type TestCRStatus struct {
-	Children ManagedChildrenList `json:"children,omitempty"`
+	Children  ManagedChildrenList `json:"children,omitempty"`
+	Inventory Inventory           `json:"inventory,omitempty"`
 }
 
 func setupScheme() *runtime.Scheme {
@@ -146,8 +157,8 @@ func TestPruner_FirstReconcile(t *testing.T) {
 		t.Fatalf("Failed to update status: %v", err)
 	}
 
-	if len(result) != 0 {
-		t.Errorf("Expected 0 pruned resources on first reconcile, got %d", len(result))
+	if len(result.Pruned()) != 0 {
+		t.Errorf("Expected 0 pruned resources on first reconcile, got %d", len(result.Pruned()))
 	}
 
 	if len(owner.Status.Children) != 1 {
@@ -275,8 +286,8 @@ func TestPruner_SecondReconcileWithPrune(t *testing.T) {
 		t.Fatalf("Failed to update status: %v", err)
 	}
 
-	if len(result) != 1 {
-		t.Errorf("Expected 1 pruned resource, got %d", len(result))
+	if len(result.Pruned()) != 1 {
+		t.Errorf("Expected 1 pruned resource, got %d", len(result.Pruned()))
 	}
 
 	if len(owner.Status.Children) != 1 {
@@ -379,8 +390,8 @@ func TestPruner_IdempotentReconcile(t *testing.T) {
 		t.Fatalf("Failed to update status: %v", err)
 	}
 
-	if len(result2) != 0 {
-		t.Errorf("Expected 0 pruned resources on idempotent reconcile, got %d", len(result2))
+	if len(result2.Pruned()) != 0 {
+		t.Errorf("Expected 0 pruned resources on idempotent reconcile, got %d", len(result2.Pruned()))
 	}
 }
 
@@ -460,7 +471,7 @@ func TestPruner_DryRun(t *testing.T) {
 	)
 
 	// Second reconcile with dry-run (no deployment desired)
-	pruned, err := prunerDryRun.Prune(context.Background())
+	result, err := prunerDryRun.Prune(context.Background())
 	if err != nil {
 		t.Fatalf("Dry-run Prune failed: %v", err)
 	}
@@ -469,8 +480,8 @@ func TestPruner_DryRun(t *testing.T) {
 	}
 
 	// In dry-run mode with client.DryRunAll, the delete succeeds but doesn't actually remove the resource
-	if len(pruned) != 1 {
-		t.Errorf("Expected 1 pruned resource in dry-run, got %d", len(pruned))
+	if len(result.Pruned()) != 1 {
+		t.Errorf("Expected 1 pruned resource in dry-run, got %d", len(result.Pruned()))
 	}
 
 	// Verify deployment still exists (dry-run doesn't actually delete)
@@ -627,8 +638,8 @@ func TestPruner_ErrorHandlerReturnsError(t *testing.T) {
 		t.Fatalf("Failed to update status: %v", err)
 	}
 
-	if len(result) != 1 {
-		t.Errorf("Expected 1 pruned resource, got %d", len(result))
+	if len(result.Pruned()) != 1 {
+		t.Errorf("Expected 1 pruned resource, got %d", len(result.Pruned()))
 	}
 }
 
@@ -709,3 +720,731 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+func newTestOwner(generation int64) *TestCR {
+	return &TestCR{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "TestCR",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-owner",
+			Namespace:  "default",
+			UID:        "test-uid",
+			Generation: generation,
+		},
+	}
+}
+
+func newTestDeployment(name string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name + "-uid"),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": name},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": name},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "test", Image: "nginx"}},
+				},
+			},
+		},
+	}
+}
+
+// pruneAfterRemoval marks objs in generation 1 and prunes them in generation 2.
+func pruneAfterRemoval(t *testing.T, cl client.Client, owner *TestCR, objs []client.Object, opts ...Option) (*Pruner, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	for _, obj := range objs {
+		if err := cl.Create(ctx, obj); err != nil {
+			t.Fatalf("Failed to create child: %v", err)
+		}
+		if err := pruner.MarkReconciled(obj); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	owner.SetGeneration(owner.GetGeneration() + 1)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	_, err := pruner2.Prune(ctx)
+	return pruner2, err
+}
+
+func TestPruner_UpdatedChildIsNotPruned(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&TestCR{}).
+		Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	pruner := NewPruner(cl, owner, &owner.Status.Children, WithScheme(scheme))
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+
+	// Updating the child bumps its ResourceVersion
+	deployment.Spec.Template.Spec.Containers[0].Image = "nginx:latest"
+	if err := cl.Update(ctx, deployment); err != nil {
+		t.Fatalf("Failed to update deployment: %v", err)
+	}
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled after update failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	if len(owner.Status.Children) != 1 {
+		t.Fatalf("Expected 1 child in status, got %d", len(owner.Status.Children))
+	}
+	if rv := owner.Status.Children[0].ObjectReference.ResourceVersion; rv != "" {
+		t.Errorf("Expected ResourceVersion to be stripped from status, got %q", rv)
+	}
+
+	// Next generation: the updated child is still desired
+	owner.SetGeneration(2)
+	pruner2 := NewPruner(cl, owner, &owner.Status.Children, WithScheme(scheme))
+	if err := pruner2.MarkReconciled(deployment); err != nil {
+		t.Fatalf("Second MarkReconciled failed: %v", err)
+	}
+	result, err := pruner2.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	if len(result.Pruned()) != 0 {
+		t.Errorf("Expected 0 pruned resources, got %d", len(result.Pruned()))
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}); err != nil {
+		t.Errorf("Deployment should still exist: %v", err)
+	}
+}
+
+func TestNewPruner_CompactsDuplicateChildren(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(2)
+	ref := corev1.ObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Namespace:  "default",
+		Name:       "test-deployment",
+		UID:        "test-deployment-uid",
+	}
+	oldRef, newRef := ref, ref
+	oldRef.ResourceVersion = "1"
+	newRef.ResourceVersion = "2"
+	owner.Status.Children = ManagedChildrenList{
+		{ObjectReference: oldRef, ObservedGeneration: 1},
+		{ObjectReference: newRef, ObservedGeneration: 2},
+	}
+
+	_ = NewPruner(cl, owner, &owner.Status.Children, WithScheme(scheme))
+
+	if len(owner.Status.Children) != 1 {
+		t.Fatalf("Expected duplicates to be merged into 1 child, got %d", len(owner.Status.Children))
+	}
+	child := owner.Status.Children[0]
+	if child.ObjectReference != ref {
+		t.Errorf("Expected sanitized reference %+v, got %+v", ref, child.ObjectReference)
+	}
+	if child.ObservedGeneration != 2 {
+		t.Errorf("Expected ObservedGeneration 2, got %d", child.ObservedGeneration)
+	}
+}
+
+func TestPruner_DeleteUsesUIDPrecondition(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+
+	var preconditions *metav1.Preconditions
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deleteOpts := &client.DeleteOptions{}
+				deleteOpts.ApplyOptions(opts)
+				preconditions = deleteOpts.Preconditions
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	pruner := NewPruner(cl, owner, &owner.Status.Children, WithScheme(scheme))
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	owner.SetGeneration(2)
+	pruner2 := NewPruner(cl, owner, &owner.Status.Children, WithScheme(scheme))
+	if _, err := pruner2.Prune(ctx); err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	if preconditions == nil || preconditions.UID == nil {
+		t.Fatalf("Expected delete to carry a UID precondition")
+	}
+	if *preconditions.UID != deployment.UID {
+		t.Errorf("Expected UID precondition %q, got %q", deployment.UID, *preconditions.UID)
+	}
+}
+
+func TestPruner_SkipsRecreatedChild(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	deployment.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "TestCR",
+		Name:       owner.Name,
+		UID:        owner.UID,
+	}}
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	pruner := NewPruner(cl, owner, &owner.Status.Children,
+		WithScheme(scheme),
+		WithOwnerReferenceCheck(true),
+	)
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Someone else re-creates an object with the same name
+	if err := cl.Delete(ctx, deployment); err != nil {
+		t.Fatalf("Failed to delete deployment: %v", err)
+	}
+	foreign := newTestDeployment("test-deployment")
+	foreign.UID = "foreign-uid"
+	if err := cl.Create(ctx, foreign); err != nil {
+		t.Fatalf("Failed to re-create deployment: %v", err)
+	}
+
+	owner.SetGeneration(2)
+	pruner2 := NewPruner(cl, owner, &owner.Status.Children,
+		WithScheme(scheme),
+		WithOwnerReferenceCheck(true),
+	)
+	result, err := pruner2.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	if len(result.Pruned()) != 0 {
+		t.Errorf("Expected 0 pruned resources, got %d", len(result.Pruned()))
+	}
+	skipped := pruner2.Skipped()
+	if len(skipped) != 1 || skipped[0].Reason != SkipReasonIdentityMismatch {
+		t.Errorf("Expected 1 child skipped for identity mismatch, got %+v", skipped)
+	}
+	if len(owner.Status.Children) != 0 {
+		t.Errorf("Expected skipped child to be dropped from status, got %d children", len(owner.Status.Children))
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(foreign), &appsv1.Deployment{}); err != nil {
+		t.Errorf("Foreign deployment should still exist: %v", err)
+	}
+}
+
+func TestPruner_TrackingLabelAllowsDelete(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	deployment.Labels = map[string]string{"app.kubernetes.io/managed-by": "test-controller"}
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	opts := []Option{
+		WithScheme(scheme),
+		WithTrackingLabel("app.kubernetes.io/managed-by", "test-controller"),
+	}
+	pruner := NewPruner(cl, owner, &owner.Status.Children, opts...)
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	owner.SetGeneration(2)
+	pruner2 := NewPruner(cl, owner, &owner.Status.Children, opts...)
+	result, err := pruner2.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	if len(result.Pruned()) != 1 {
+		t.Errorf("Expected 1 pruned resource, got %d", len(result.Pruned()))
+	}
+	if len(pruner2.Skipped()) != 0 {
+		t.Errorf("Expected no skipped children, got %+v", pruner2.Skipped())
+	}
+}
+
+func TestInventoryPruner_PrunesAfterPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	dep1 := newTestDeployment("test-deployment-1")
+	dep2 := newTestDeployment("test-deployment-2")
+	for _, dep := range []*appsv1.Deployment{dep1, dep2} {
+		if err := cl.Create(ctx, dep); err != nil {
+			t.Fatalf("Failed to create deployment: %v", err)
+		}
+	}
+
+	// Generation 1 completes
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.MarkReconciled(dep1); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if got := owner.Status.Inventory.CompletedGeneration; got != 1 {
+		t.Fatalf("Expected CompletedGeneration 1, got %d", got)
+	}
+
+	// Generation 2 marks dep2, then the reconcile fails before Prune
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner2.MarkReconciled(dep2); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if got := owner.Status.Inventory.CompletedGeneration; got != 1 {
+		t.Fatalf("Expected CompletedGeneration to stay 1 without Prune, got %d", got)
+	}
+
+	// The retried reconcile still prunes generation 1 leftovers
+	pruner3 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner3.MarkReconciled(dep2); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	result, err := pruner3.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Retried Prune failed: %v", err)
+	}
+
+	if len(result.Pruned()) != 1 || result.Pruned()[0].Name != dep1.Name {
+		t.Errorf("Expected %s to be pruned, got %+v", dep1.Name, result.Pruned())
+	}
+	if got := owner.Status.Inventory.CompletedGeneration; got != 2 {
+		t.Errorf("Expected CompletedGeneration 2, got %d", got)
+	}
+}
+
+func TestInventoryPruner_FailedPruneDoesNotComplete(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				return errors.New("delete refused")
+			},
+		}).
+		Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if _, err := pruner2.Prune(ctx); err == nil {
+		t.Fatalf("Expected Prune to fail")
+	}
+
+	if got := owner.Status.Inventory.CompletedGeneration; got != 1 {
+		t.Errorf("Expected CompletedGeneration to stay 1 after a failed Prune, got %d", got)
+	}
+	if len(owner.Status.Inventory.Children) != 1 {
+		t.Errorf("Expected the child to stay in the inventory, got %d children", len(owner.Status.Inventory.Children))
+	}
+}
+
+func TestPruner_WaitForDeletion(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	deployment.Finalizers = []string{"example.com/slow"}
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	opts := []Option{WithScheme(scheme), WithWaitForDeletion(5 * time.Second)}
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Generation 2 drops the deployment, which stays Terminating
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	result, err := pruner2.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	if len(result.Pruned()) != 0 {
+		t.Errorf("Expected no confirmed deletion yet, got %+v", result.Pruned())
+	}
+	if pruner2.RequeueAfter() != 5*time.Second {
+		t.Errorf("Expected a requeue hint of 5s, got %v", pruner2.RequeueAfter())
+	}
+	children := owner.Status.Inventory.Children
+	if len(children) != 1 || children[0].State != ChildStateDeleting {
+		t.Fatalf("Expected the child to stay in the Deleting state, got %+v", children)
+	}
+	if got := owner.Status.Inventory.CompletedGeneration; got != 1 {
+		t.Errorf("Expected CompletedGeneration to stay 1 while deleting, got %d", got)
+	}
+
+	// The finalizer completes; the follow-up reconcile confirms the deletion
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("Failed to get deployment: %v", err)
+	}
+	deployment.Finalizers = nil
+	if err := cl.Update(ctx, deployment); err != nil {
+		t.Fatalf("Failed to remove deployment finalizer: %v", err)
+	}
+
+	pruner3 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	result, err = pruner3.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Third Prune failed: %v", err)
+	}
+
+	if len(result.Pruned()) != 1 {
+		t.Errorf("Expected 1 confirmed deletion, got %+v", result.Pruned())
+	}
+	if pruner3.RequeueAfter() != 0 {
+		t.Errorf("Expected no requeue hint, got %v", pruner3.RequeueAfter())
+	}
+	if len(owner.Status.Inventory.Children) != 0 {
+		t.Errorf("Expected empty inventory, got %+v", owner.Status.Inventory.Children)
+	}
+	if got := owner.Status.Inventory.CompletedGeneration; got != 2 {
+		t.Errorf("Expected CompletedGeneration 2, got %d", got)
+	}
+}
+
+func TestPruner_ConcurrentDeletion(t *testing.T) {
+	scheme := setupScheme()
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				mu.Lock()
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				mu.Unlock()
+
+				time.Sleep(20 * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+
+				if obj.GetName() == "test-deployment-2" || obj.GetName() == "test-deployment-4" {
+					return errors.New("delete failed")
+				}
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	var handled []string
+	handler := func(ctx context.Context, err error, obj client.Object) error {
+		handled = append(handled, obj.GetName())
+		return err
+	}
+
+	var objs []client.Object
+	for _, name := range []string{"test-deployment-1", "test-deployment-2", "test-deployment-3", "test-deployment-4", "test-deployment-5", "test-deployment-6"} {
+		objs = append(objs, newTestDeployment(name))
+	}
+	owner := newTestOwner(1)
+	pruner, err := pruneAfterRemoval(t, cl, owner, objs, WithScheme(scheme), WithConcurrency(3), WithErrorHandler(handler))
+	if err == nil {
+		t.Fatal("Expected Prune to return the handler errors")
+	}
+
+	if maxInFlight < 2 || maxInFlight > 3 {
+		t.Errorf("Expected between 2 and 3 deletions in flight, got %d", maxInFlight)
+	}
+	if want := []string{"test-deployment-2", "test-deployment-4"}; !slices.Equal(handled, want) {
+		t.Errorf("Expected the error handler to be called in inventory order %v, got %v", want, handled)
+	}
+
+	var pruned []string
+	for _, ref := range pruner.pruned {
+		pruned = append(pruned, ref.Name)
+	}
+	if want := []string{"test-deployment-1", "test-deployment-3", "test-deployment-5", "test-deployment-6"}; !slices.Equal(pruned, want) {
+		t.Errorf("Expected pruned children in inventory order %v, got %v", want, pruned)
+	}
+
+	var remaining []string
+	for _, child := range owner.Status.Inventory.Children {
+		remaining = append(remaining, child.ObjectReference.Name)
+	}
+	if want := []string{"test-deployment-2", "test-deployment-4"}; !slices.Equal(remaining, want) {
+		t.Errorf("Expected failed children to stay in the inventory, got %v", remaining)
+	}
+}
+
+func TestPruner_ConcurrentMarkReconciled(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	var deployments []*appsv1.Deployment
+	for i := range 20 {
+		dep := newTestDeployment(fmt.Sprintf("test-deployment-%d", i))
+		if err := cl.Create(ctx, dep); err != nil {
+			t.Fatalf("Failed to create deployment: %v", err)
+		}
+		deployments = append(deployments, dep)
+	}
+
+	// Generation 1 marks every deployment from its own goroutine, then prunes
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	var wg sync.WaitGroup
+	for _, dep := range deployments {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pruner.MarkReconciled(dep); err != nil {
+				t.Errorf("MarkReconciled failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if got := len(owner.Status.Inventory.Children); got != len(deployments) {
+		t.Fatalf("Expected %d children, got %d", len(deployments), got)
+	}
+
+	// Generation 2 races every mark against Prune
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	markErrs := make([]error, len(deployments))
+	for i, dep := range deployments {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			markErrs[i] = pruner2.MarkReconciled(dep)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := pruner2.Prune(ctx); err != nil {
+			t.Errorf("Second Prune failed: %v", err)
+		}
+	}()
+	wg.Wait()
+
+	// A mark either landed before Prune and protected its child, or was
+	// refused and its child was pruned
+	for i, dep := range deployments {
+		marked := markErrs[i] == nil
+		if !marked && !errors.Is(markErrs[i], ErrPruneStarted) {
+			t.Fatalf("Expected ErrPruneStarted, got %v", markErrs[i])
+		}
+		err := cl.Get(ctx, client.ObjectKeyFromObject(dep), &appsv1.Deployment{})
+		if exists := err == nil; exists != marked {
+			t.Errorf("%s: marked %t, but exists %t (%v)", dep.Name, marked, exists, err)
+		}
+		tracked := owner.Status.Inventory.Children.Index(IdentityFromReference(corev1.ObjectReference{
+			APIVersion: "apps/v1", Kind: "Deployment", Namespace: dep.Namespace, Name: dep.Name,
+		})) >= 0
+		if tracked != marked {
+			t.Errorf("%s: marked %t, but tracked %t", dep.Name, marked, tracked)
+		}
+	}
+}
+
+func TestPruner_MarkAfterPruneIsRefused(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	deps := createDeployments(t, cl, "kept", "late")
+
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if _, err := markAndPrune(t, pruner, deps...); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Generation 2 prunes the late child before its worker marks it
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if _, err := markAndPrune(t, pruner2, deps[0]); err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if err := pruner2.MarkReconciled(deps[1]); !errors.Is(err, ErrPruneStarted) {
+		t.Errorf("Expected ErrPruneStarted from MarkReconciled, got %v", err)
+	}
+	if err := pruner2.Apply(ctx, deps[1]); !errors.Is(err, ErrPruneStarted) {
+		t.Errorf("Expected ErrPruneStarted from Apply, got %v", err)
+	}
+	if children := owner.Status.Inventory.Children; len(children) != 1 || children[0].ObjectReference.Name != "kept" {
+		t.Errorf("Expected only the kept child to be tracked, got %+v", children)
+	}
+}
+
+func TestPruner_MarkReconciledRef(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	// Generation 2 leaves the deployment untouched and only marks it by reference
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+	if err := pruner2.MarkReconciledRef(gvk, client.ObjectKeyFromObject(deployment), ""); err != nil {
+		t.Fatalf("MarkReconciledRef failed: %v", err)
+	}
+	result, err := pruner2.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+
+	if len(result.Pruned()) != 0 {
+		t.Errorf("Expected nothing to be pruned, got %+v", result.Pruned())
+	}
+	children := owner.Status.Inventory.Children
+	if len(children) != 1 || children[0].ObservedGeneration != 2 {
+		t.Fatalf("Expected the child to be recorded in generation 2, got %+v", children)
+	}
+	if children[0].ObjectReference.UID != deployment.UID {
+		t.Errorf("Expected the recorded UID %q to be kept, got %q", deployment.UID, children[0].ObjectReference.UID)
+	}
+
+	if err := pruner2.MarkReconciledRef(schema.GroupVersionKind{}, client.ObjectKey{Name: "x"}, ""); err == nil {
+		t.Error("Expected an error without a kind")
+	}
+}
+
+func TestPruner_MarkReconciledWithoutScheme(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	owner := newTestOwner(1)
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory)
+
+	gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace("default")
+	u.SetName("from-dynamic-client")
+	u.SetUID("dynamic-uid")
+
+	meta := &metav1.PartialObjectMetadata{}
+	meta.SetGroupVersionKind(gvk)
+	meta.SetNamespace("default")
+	meta.SetName("from-metadata-cache")
+	meta.SetUID("metadata-uid")
+
+	for _, obj := range []client.Object{u, meta} {
+		if err := pruner.MarkReconciled(obj); err != nil {
+			t.Fatalf("MarkReconciled failed for %s: %v", obj.GetName(), err)
+		}
+	}
+
+	children := owner.Status.Inventory.Children
+	if len(children) != 2 {
+		t.Fatalf("Expected 2 children, got %+v", children)
+	}
+	for i, obj := range []client.Object{u, meta} {
+		ref := children[i].ObjectReference
+		if ref.APIVersion != "apps/v1" || ref.Kind != "Deployment" || ref.Name != obj.GetName() || ref.UID != obj.GetUID() {
+			t.Errorf("Unexpected reference for %s: %+v", obj.GetName(), ref)
+		}
+	}
+}
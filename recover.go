@@ -0,0 +1,121 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RecoverInventory rebuilds inventory entries for children that exist in the
+// cluster but are missing from the inventory, for example after the owner's
+// status was lost to a backup restore or a CRD reinstall.
+//
+// Candidates of the given kinds are listed as follows:
+//   - with a non-nil selector, objects matching it in all namespaces;
+//   - otherwise, with WithTrackingLabel, objects carrying the tracking label in all namespaces;
+//   - otherwise, objects in the owner's namespace with an ownerReference to the owner's UID.
+//
+// The ConfigMap or Secret of NewConfigMapStore and NewSecretStore is never
+// recovered, even though it has an ownerReference to the owner.
+//
+// Recovered children are recorded at the previous generation, so the normal
+// Prune flow deletes every one of them that is not marked in this session.
+// Call RecoverInventory before Prune, typically when the inventory is empty.
+// It returns the references of the recovered children.
+//
+// Example:
+//
+//	if len(myCR.Status.Inventory.Children) == 0 {
+//	    _, err := pruner.RecoverInventory(ctx, []schema.GroupVersionKind{
+//	        appsv1.SchemeGroupVersion.WithKind("Deployment"),
+//	        corev1.SchemeGroupVersion.WithKind("Service"),
+//	    }, nil)
+//	}
+func (p *Pruner) RecoverInventory(ctx context.Context, kinds []schema.GroupVersionKind, selector labels.Selector) ([]corev1.ObjectReference, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if selector == nil && p.trackingLabelKey != "" {
+		selector = labels.SelectorFromSet(labels.Set{p.trackingLabelKey: p.trackingLabelValue})
+	}
+
+	var listOpts []client.ListOption
+	if selector != nil {
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
+	} else if ns := p.owner.GetNamespace(); ns != "" {
+		// ownerReferences cannot cross namespaces
+		listOpts = append(listOpts, client.InNamespace(ns))
+	}
+
+	previousGen := max(p.generation-1, 0)
+	var recovered []corev1.ObjectReference
+
+	for _, gvk := range kinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := p.client.List(ctx, list, listOpts...); err != nil {
+			return recovered, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			if selector == nil && !hasOwnerReference(item, p.owner) {
+				continue
+			}
+
+			ref := corev1.ObjectReference{
+				APIVersion: gvk.GroupVersion().String(),
+				Kind:       gvk.Kind,
+				Namespace:  item.GetNamespace(),
+				Name:       item.GetName(),
+				UID:        item.GetUID(),
+			}
+			id := IdentityFromReference(ref)
+			if p.statusChildren.Index(id) >= 0 || p.isInventoryObject(id) {
+				continue
+			}
+
+			*p.statusChildren = append(*p.statusChildren, ManagedChild{
+				ObjectReference:    ref,
+				ObservedGeneration: previousGen,
+			})
+			recovered = append(recovered, ref)
+		}
+	}
+
+	// Recovered children belong to an already applied generation
+	if len(recovered) > 0 && p.lastAppliedGen < previousGen {
+		p.lastAppliedGen = previousGen
+	}
+
+	return recovered, nil
+}
+
+// hasOwnerReference reports whether obj has an ownerReference to owner.
+func hasOwnerReference(obj, owner client.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
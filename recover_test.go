@@ -0,0 +1,121 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPruner_RecoverInventoryByOwnerReference(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	// Status was lost: generation 3 with an empty inventory
+	owner := newTestOwner(3)
+	ownerRef := metav1.OwnerReference{APIVersion: "v1", Kind: "TestCR", Name: owner.Name, UID: owner.UID}
+
+	desired := newTestDeployment("desired")
+	desired.OwnerReferences = []metav1.OwnerReference{ownerRef}
+	leftover := newTestDeployment("leftover")
+	leftover.OwnerReferences = []metav1.OwnerReference{ownerRef}
+	unrelated := newTestDeployment("unrelated")
+	for _, obj := range []client.Object{desired, leftover, unrelated} {
+		if err := cl.Create(ctx, obj); err != nil {
+			t.Fatalf("Failed to create deployment: %v", err)
+		}
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	recovered, err := pruner.RecoverInventory(ctx, []schema.GroupVersionKind{
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+	}, nil)
+	if err != nil {
+		t.Fatalf("RecoverInventory failed: %v", err)
+	}
+	if len(recovered) != 2 {
+		t.Fatalf("Expected 2 recovered children, got %+v", recovered)
+	}
+	for _, child := range owner.Status.Inventory.Children {
+		if child.ObservedGeneration != 2 {
+			t.Errorf("Expected %s to be recovered at generation 2, got %d", child.ObjectReference.Name, child.ObservedGeneration)
+		}
+	}
+
+	if err := pruner.MarkReconciled(desired); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	result, err := pruner.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	if len(result.Pruned()) != 1 || result.Pruned()[0].Name != leftover.Name {
+		t.Errorf("Expected %s to be pruned, got %+v", leftover.Name, result.Pruned())
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(unrelated), &appsv1.Deployment{}); err != nil {
+		t.Errorf("Unrelated deployment should still exist: %v", err)
+	}
+	if len(owner.Status.Inventory.Children) != 1 {
+		t.Errorf("Expected 1 child in the inventory, got %+v", owner.Status.Inventory.Children)
+	}
+}
+
+func TestPruner_RecoverInventoryBySelector(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	labelled := newTestDeployment("labelled")
+	labelled.Namespace = "other"
+	labelled.Labels = map[string]string{"example.com/owner": owner.Name}
+	unlabelled := newTestDeployment("unlabelled")
+	for _, obj := range []client.Object{labelled, unlabelled} {
+		if err := cl.Create(ctx, obj); err != nil {
+			t.Fatalf("Failed to create deployment: %v", err)
+		}
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	recovered, err := pruner.RecoverInventory(ctx, []schema.GroupVersionKind{
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+	}, labels.SelectorFromSet(labels.Set{"example.com/owner": owner.Name}))
+	if err != nil {
+		t.Fatalf("RecoverInventory failed: %v", err)
+	}
+
+	if len(recovered) != 1 || recovered[0].Name != labelled.Name || recovered[0].Namespace != "other" {
+		t.Fatalf("Expected only %s to be recovered, got %+v", labelled.Name, recovered)
+	}
+
+	// Recovering twice does not duplicate entries
+	if _, err := pruner.RecoverInventory(ctx, []schema.GroupVersionKind{
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+	}, labels.SelectorFromSet(labels.Set{"example.com/owner": owner.Name})); err != nil {
+		t.Fatalf("Second RecoverInventory failed: %v", err)
+	}
+	if len(owner.Status.Inventory.Children) != 1 {
+		t.Errorf("Expected 1 child in the inventory, got %+v", owner.Status.Inventory.Children)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PruneOutcome describes what Prune did with a single inventory child.
+type PruneOutcome string
+
+const (
+	// OutcomeDeleted means the child was deleted.
+	OutcomeDeleted PruneOutcome = "Deleted"
+
+	// OutcomeAlreadyGone means the child no longer existed in the cluster.
+	OutcomeAlreadyGone PruneOutcome = "AlreadyGone"
+
+	// OutcomeDeleting means the delete was accepted but the child still exists.
+	// It is reported when WithWaitForDeletion is used, and for children of a
+	// deletion wave followed by another one, see DeletionOrder.
+	OutcomeDeleting PruneOutcome = "Deleting"
+
+	// OutcomeFailed means the deletion failed and the error handler returned an error.
+	OutcomeFailed PruneOutcome = "Failed"
+
+	// OutcomeIgnored means the deletion failed but the error handler ignored the error.
+	OutcomeIgnored PruneOutcome = "Ignored"
+
+	// OutcomeKept means the child was not a prune candidate.
+	OutcomeKept PruneOutcome = "Kept"
+
+	// OutcomeSkipped means the child was a prune candidate but was left in place
+	// and removed from the inventory.
+	OutcomeSkipped PruneOutcome = "Skipped"
+
+	// OutcomeDryRun means the child would have been deleted outside of dry-run mode.
+	OutcomeDryRun PruneOutcome = "DryRun"
+)
+
+// Reasons reported in ChildResult. Skipped children report one of the
+// SkipReason constants instead.
+const (
+	// ReasonDesired means the child was marked as reconciled in this session.
+	ReasonDesired = "marked as reconciled"
+
+	// ReasonCurrentGeneration means the child was applied in the current generation.
+	ReasonCurrentGeneration = "applied in the current generation"
+
+	// ReasonGenerationUnchanged means the owner's generation has not changed
+	// since the last completed prune.
+	ReasonGenerationUnchanged = "generation unchanged"
+
+	// ReasonFingerprintUnchanged means neither the owner's generation nor the
+	// fingerprint have changed since the last completed prune.
+	ReasonFingerprintUnchanged = "generation and fingerprint unchanged"
+
+	// ReasonPreviousWaveIncomplete means the child's deletion wave was not
+	// started because an earlier wave failed or is still being deleted.
+	ReasonPreviousWaveIncomplete = "earlier deletion wave incomplete"
+
+	// ReasonThresholdExceeded means PruneLimits refused the prune.
+	ReasonThresholdExceeded = "prune threshold exceeded"
+
+	// ReasonNotReconciled means the child was not marked as reconciled and
+	// belongs to a previous generation.
+	ReasonNotReconciled = "not reconciled in the current generation"
+
+	// ReasonApplyFailed means Apply failed for the child in this session.
+	ReasonApplyFailed = "apply failed"
+
+	// ReasonIntentPending means the child was recorded by RecordIntent in this
+	// session but was not marked as reconciled.
+	ReasonIntentPending = "intent recorded in this session"
+
+	// ReasonTeardown means the child was removed by PruneAll.
+	ReasonTeardown = "inventory teardown"
+
+	// ReasonDeletionPending means the child is waiting for its deletion to complete.
+	ReasonDeletionPending = "waiting for deletion to complete"
+)
+
+// ChildResult is the outcome of Prune for a single inventory child.
+type ChildResult struct {
+	// ObjectReference identifies the child.
+	ObjectReference corev1.ObjectReference
+
+	// Outcome is what Prune did with the child.
+	Outcome PruneOutcome
+
+	// Reason explains the outcome.
+	Reason string
+
+	// Error is the deletion error, if any. For OutcomeFailed it is the error
+	// returned by the error handler, for OutcomeIgnored the original error.
+	Error error
+
+	// Timestamp is when the outcome was decided.
+	Timestamp time.Time
+}
+
+// PruneResult is the result of a Prune call.
+//
+// Example:
+//
+//	result, err := pruner.Prune(ctx)
+//	for _, child := range result.Children {
+//	    log.Info("pruned child", "ref", child.ObjectReference, "outcome", child.Outcome, "reason", child.Reason)
+//	}
+type PruneResult struct {
+	// Children holds one entry per inventory child, in inventory order.
+	Children []ChildResult
+
+	// Counts is the number of children per outcome.
+	Counts map[PruneOutcome]int
+
+	// RequeueAfter is non-zero when children are still being deleted, with
+	// WithWaitForDeletion or because a deletion wave must be gone before the
+	// next one starts. See DeletionOrder.
+	RequeueAfter time.Duration
+
+	pruned []corev1.ObjectReference
+}
+
+// Pruned returns the children that were deleted, already gone, ignored by the
+// error handler or deleted in dry-run mode, in deletion order. This is the list
+// Prune used to return.
+func (r *PruneResult) Pruned() []corev1.ObjectReference {
+	return r.pruned
+}
+
+// newPruneResult builds a PruneResult from per-child results.
+func newPruneResult(children []ChildResult, pruned []corev1.ObjectReference, requeueAfter time.Duration) *PruneResult {
+	counts := make(map[PruneOutcome]int)
+	for _, child := range children {
+		counts[child.Outcome]++
+	}
+	return &PruneResult{
+		Children:     children,
+		Counts:       counts,
+		RequeueAfter: requeueAfter,
+		pruned:       pruned,
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestPruneResult_Outcomes(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+
+	errFailed := errors.New("delete failed")
+	errIgnored := errors.New("delete ignored")
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				switch obj.GetName() {
+				case "failed":
+					return errFailed
+				case "ignored":
+					return errIgnored
+				}
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	handler := func(ctx context.Context, err error, obj client.Object) error {
+		if errors.Is(err, errIgnored) {
+			return nil
+		}
+		return err
+	}
+	opts := []Option{WithScheme(scheme), WithErrorHandler(handler)}
+
+	owner := newTestOwner(1)
+	desired := newTestDeployment("desired")
+	gone := newTestDeployment("gone")
+	skipped := newTestDeployment("skipped")
+	skipped.Annotations = map[string]string{PruneAnnotation: PruneDisabled}
+	failed := newTestDeployment("failed")
+	ignored := newTestDeployment("ignored")
+	deleted := newTestDeployment("deleted")
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	for _, obj := range []client.Object{desired, gone, skipped, failed, ignored, deleted} {
+		if err := cl.Create(ctx, obj); err != nil {
+			t.Fatalf("Failed to create %s: %v", obj.GetName(), err)
+		}
+		if err := pruner.MarkReconciled(obj); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+	if err := cl.Delete(ctx, gone); err != nil {
+		t.Fatalf("Failed to delete deployment: %v", err)
+	}
+
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, opts...)
+	if err := pruner2.MarkReconciled(desired); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	result, err := pruner2.Prune(ctx)
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("Expected the delete error, got %v", err)
+	}
+
+	want := []struct {
+		name    string
+		outcome PruneOutcome
+		reason  string
+	}{
+		{"desired", OutcomeKept, ReasonDesired},
+		{"gone", OutcomeAlreadyGone, ReasonNotReconciled},
+		{"skipped", OutcomeSkipped, SkipReasonAnnotation},
+		{"failed", OutcomeFailed, ReasonNotReconciled},
+		{"ignored", OutcomeIgnored, ReasonNotReconciled},
+		{"deleted", OutcomeDeleted, ReasonNotReconciled},
+	}
+	if len(result.Children) != len(want) {
+		t.Fatalf("Expected %d child results, got %+v", len(want), result.Children)
+	}
+	for i, w := range want {
+		got := result.Children[i]
+		if got.ObjectReference.Name != w.name || got.Outcome != w.outcome || got.Reason != w.reason {
+			t.Errorf("Child %d: expected %s %s (%s), got %s %s (%s)",
+				i, w.name, w.outcome, w.reason, got.ObjectReference.Name, got.Outcome, got.Reason)
+		}
+		if got.Timestamp.IsZero() {
+			t.Errorf("Child %s: expected a timestamp", w.name)
+		}
+	}
+	if !errors.Is(result.Children[3].Error, errFailed) || !errors.Is(result.Children[4].Error, errIgnored) {
+		t.Errorf("Expected the errors to be reported, got %v and %v", result.Children[3].Error, result.Children[4].Error)
+	}
+	if result.Counts[OutcomeKept] != 1 || result.Counts[OutcomeDeleted] != 1 || result.Counts[OutcomeFailed] != 1 {
+		t.Errorf("Unexpected counts: %v", result.Counts)
+	}
+
+	// The compatibility list keeps the deleted, already gone and ignored children
+	var pruned []string
+	for _, ref := range result.Pruned() {
+		pruned = append(pruned, ref.Name)
+	}
+	if len(pruned) != 3 || pruned[0] != "gone" || pruned[1] != "ignored" || pruned[2] != "deleted" {
+		t.Errorf("Expected gone, ignored and deleted to be pruned, got %v", pruned)
+	}
+}
+
+func TestPruneResult_DryRun(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("First Prune failed: %v", err)
+	}
+
+	owner.SetGeneration(2)
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme), WithDryRun(true))
+	result, err := pruner2.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Dry-run Prune failed: %v", err)
+	}
+
+	if len(result.Children) != 1 || result.Children[0].Outcome != OutcomeDryRun {
+		t.Errorf("Expected a DryRun outcome, got %+v", result.Children)
+	}
+	if result.Counts[OutcomeDryRun] != 1 || len(result.Pruned()) != 1 {
+		t.Errorf("Expected the dry-run deletion to be counted as pruned, got %v", result.Counts)
+	}
+}
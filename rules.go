@@ -0,0 +1,78 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PruneRule customizes how children of a given GroupKind are pruned.
+// Rules are registered with WithPruneRule and compose with the pruner-wide
+// delete options and dry-run mode.
+type PruneRule struct {
+	// PropagationPolicy is sent with the delete call when set.
+	PropagationPolicy *metav1.DeletionPropagation
+
+	// GracePeriodSeconds is sent with the delete call when set.
+	GracePeriodSeconds *int64
+
+	// Protected children are never deleted. When they leave the desired set
+	// they are reported as skipped and dropped from the inventory.
+	Protected bool
+
+	// ErrorHandler replaces the pruner's error handler for this kind when set.
+	ErrorHandler ErrorHandlerFunc
+}
+
+// deleteOptions returns the delete options contributed by the rule.
+func (r PruneRule) deleteOptions() []client.DeleteOption {
+	var opts []client.DeleteOption
+	if r.PropagationPolicy != nil {
+		opts = append(opts, client.PropagationPolicy(*r.PropagationPolicy))
+	}
+	if r.GracePeriodSeconds != nil {
+		opts = append(opts, client.GracePeriodSeconds(*r.GracePeriodSeconds))
+	}
+	return opts
+}
+
+// ruleFor returns the rule registered for the given kind, if any.
+func (p *Pruner) ruleFor(gk schema.GroupKind) PruneRule {
+	return p.rules[gk]
+}
+
+// errorHandlerFor returns the error handler to use for the given kind.
+func (p *Pruner) errorHandlerFor(gk schema.GroupKind) ErrorHandlerFunc {
+	if handler := p.ruleFor(gk).ErrorHandler; handler != nil {
+		return handler
+	}
+	return p.errorHandler
+}
+
+// deleteOptionsFor assembles the delete options for a child: pruner-wide
+// options first, then the kind's rule, then dry-run and the UID precondition.
+func (p *Pruner) deleteOptionsFor(child ManagedChild) []client.DeleteOption {
+	opts := append([]client.DeleteOption{}, p.deleteOpts...)
+	opts = append(opts, p.ruleFor(child.Identity().GroupKind()).deleteOptions()...)
+	if p.dryRun {
+		opts = append(opts, client.DryRunAll)
+	}
+	if uid := child.ObjectReference.UID; uid != "" {
+		opts = append(opts, client.Preconditions{UID: &uid})
+	}
+	return opts
+}
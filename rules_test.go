@@ -0,0 +1,136 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestPruneRule_DeleteOptionsComposeWithDryRun(t *testing.T) {
+	scheme := setupScheme()
+
+	deleteOpts := &client.DeleteOptions{}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deleteOpts.ApplyOptions(opts)
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	foreground := metav1.DeletePropagationForeground
+	grace := int64(30)
+	_, err := pruneAfterRemoval(t, cl, newTestOwner(1),
+		[]client.Object{newTestDeployment("test-deployment")},
+		WithScheme(scheme),
+		WithDryRun(true),
+		WithPruneRule(schema.GroupKind{Group: "apps", Kind: "Deployment"}, PruneRule{
+			PropagationPolicy:  &foreground,
+			GracePeriodSeconds: &grace,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	if deleteOpts.PropagationPolicy == nil || *deleteOpts.PropagationPolicy != foreground {
+		t.Errorf("Expected Foreground propagation, got %v", deleteOpts.PropagationPolicy)
+	}
+	if deleteOpts.GracePeriodSeconds == nil || *deleteOpts.GracePeriodSeconds != grace {
+		t.Errorf("Expected grace period %d, got %v", grace, deleteOpts.GracePeriodSeconds)
+	}
+	if len(deleteOpts.DryRun) != 1 || deleteOpts.DryRun[0] != metav1.DryRunAll {
+		t.Errorf("Expected dry-run to be kept alongside the rule, got %v", deleteOpts.DryRun)
+	}
+	if deleteOpts.Preconditions == nil || deleteOpts.Preconditions.UID == nil {
+		t.Errorf("Expected the UID precondition to be kept alongside the rule")
+	}
+}
+
+func TestPruneRule_ProtectedKind(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", UID: "data-uid"},
+	}
+	deployment := newTestDeployment("test-deployment")
+
+	pruner, err := pruneAfterRemoval(t, cl, owner, []client.Object{pvc, deployment},
+		WithScheme(scheme),
+		WithPruneRule(schema.GroupKind{Kind: "PersistentVolumeClaim"}, PruneRule{Protected: true}),
+	)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	skipped := pruner.Skipped()
+	if len(skipped) != 1 || skipped[0].ObjectReference.Name != pvc.Name || skipped[0].Reason != SkipReasonProtected {
+		t.Errorf("Expected the PVC to be skipped as protected, got %+v", skipped)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{}); err != nil {
+		t.Errorf("Protected PVC should still exist: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}); err == nil {
+		t.Errorf("Expected unprotected deployment to be deleted")
+	}
+	if len(owner.Status.Inventory.Children) != 0 {
+		t.Errorf("Expected empty inventory, got %+v", owner.Status.Inventory.Children)
+	}
+}
+
+func TestPruneRule_ErrorHandler(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				return errors.New("delete refused")
+			},
+		}).
+		Build()
+
+	var handled []string
+	_, err := pruneAfterRemoval(t, cl, newTestOwner(1),
+		[]client.Object{newTestDeployment("test-deployment")},
+		WithScheme(scheme),
+		WithPruneRule(schema.GroupKind{Group: "apps", Kind: "Deployment"}, PruneRule{
+			ErrorHandler: func(ctx context.Context, err error, obj client.Object) error {
+				handled = append(handled, obj.GetName())
+				return nil
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected the rule's error handler to ignore the error, got %v", err)
+	}
+	if len(handled) != 1 {
+		t.Errorf("Expected the rule's error handler to be called once, got %v", handled)
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// InventoryAnnotation is the default owner annotation used by NewAnnotationStore.
+	InventoryAnnotation = "reconcileprune.io/inventory"
+
+	// inventoryDataKey is the ConfigMap or Secret key holding the inventory.
+	inventoryDataKey = "inventory"
+)
+
+// InventoryStore loads and saves the inventory of a Pruner.
+// Use it with NewPrunerWithStore when the owner's status cannot hold the
+// inventory, for example because the owner has no status subresource or the
+// inventory is too large.
+type InventoryStore interface {
+	// Load returns the stored inventory, or an empty one if nothing is stored yet.
+	Load(ctx context.Context) (*Inventory, error)
+
+	// Save persists the inventory.
+	Save(ctx context.Context, inventory *Inventory) error
+}
+
+// statusStore keeps the inventory in a field of the owner's status.
+type statusStore struct {
+	inventory *Inventory
+}
+
+// NewStatusStore returns a store backed by an Inventory field in the owner's
+// status. Save only updates that field in memory: persisting it is left to the
+// caller's status update, as with NewInventoryPruner.
+func NewStatusStore(inventory *Inventory) InventoryStore {
+	return &statusStore{inventory: inventory}
+}
+
+func (s *statusStore) Load(_ context.Context) (*Inventory, error) {
+	return s.inventory, nil
+}
+
+func (s *statusStore) Save(_ context.Context, inventory *Inventory) error {
+	if inventory != s.inventory {
+		inventory.DeepCopyInto(s.inventory)
+	}
+	return nil
+}
+
+// annotationStore keeps the inventory as JSON in an annotation of the owner.
+type annotationStore struct {
+	client client.Client
+	owner  client.Object
+	key    string
+}
+
+// NewAnnotationStore returns a store keeping the inventory as JSON in an
+// annotation of the owner. It suits owners without a status subresource.
+// The key defaults to InventoryAnnotation when empty.
+func NewAnnotationStore(c client.Client, owner client.Object, key string) InventoryStore {
+	if key == "" {
+		key = InventoryAnnotation
+	}
+	return &annotationStore{client: c, owner: owner, key: key}
+}
+
+func (s *annotationStore) Load(_ context.Context) (*Inventory, error) {
+	return decodeInventory([]byte(s.owner.GetAnnotations()[s.key]))
+}
+
+func (s *annotationStore) Save(ctx context.Context, inventory *Inventory) error {
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory: %w", err)
+	}
+
+	before := s.owner.DeepCopyObject().(client.Object)
+	annotations := s.owner.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[s.key] = string(data)
+	s.owner.SetAnnotations(annotations)
+
+	if err := s.client.Patch(ctx, s.owner, client.MergeFrom(before)); err != nil {
+		return fmt.Errorf("failed to save inventory annotation: %w", err)
+	}
+	return nil
+}
+
+// objectStore keeps the inventory in a dedicated ConfigMap or Secret.
+type objectStore struct {
+	client    client.Client
+	owner     client.Object
+	kind      string
+	key       client.ObjectKey
+	keyErr    error
+	newObject func() client.Object
+	getData   func(obj client.Object) []byte
+	setData   func(obj client.Object, data []byte)
+}
+
+// NewConfigMapStore returns a store keeping the inventory in a ConfigMap named
+// "<owner-name>-<owner-kind>-inventory", for example "web-myapp-inventory".
+// The namespace defaults to the owner's namespace and must be set for
+// cluster-scoped owners. When the ConfigMap lives next to the owner it gets an
+// ownerReference, so it is garbage collected with the owner. The Pruner never
+// recovers or prunes the ConfigMap itself.
+func NewConfigMapStore(c client.Client, owner client.Object, namespace string) InventoryStore {
+	key, err := inventoryObjectKey(c, owner, namespace)
+	return &objectStore{
+		client:    c,
+		owner:     owner,
+		kind:      "ConfigMap",
+		key:       key,
+		keyErr:    err,
+		newObject: func() client.Object { return &corev1.ConfigMap{} },
+		getData: func(obj client.Object) []byte {
+			return []byte(obj.(*corev1.ConfigMap).Data[inventoryDataKey])
+		},
+		setData: func(obj client.Object, data []byte) {
+			cm := obj.(*corev1.ConfigMap)
+			if cm.Data == nil {
+				cm.Data = make(map[string]string)
+			}
+			cm.Data[inventoryDataKey] = string(data)
+		},
+	}
+}
+
+// NewSecretStore is like NewConfigMapStore but keeps the inventory in a Secret,
+// for clusters where the names of children must not be readable by everyone
+// allowed to read ConfigMaps.
+func NewSecretStore(c client.Client, owner client.Object, namespace string) InventoryStore {
+	key, err := inventoryObjectKey(c, owner, namespace)
+	return &objectStore{
+		client:    c,
+		owner:     owner,
+		kind:      "Secret",
+		key:       key,
+		keyErr:    err,
+		newObject: func() client.Object { return &corev1.Secret{} },
+		getData: func(obj client.Object) []byte {
+			return obj.(*corev1.Secret).Data[inventoryDataKey]
+		},
+		setData: func(obj client.Object, data []byte) {
+			secret := obj.(*corev1.Secret)
+			if secret.Data == nil {
+				secret.Data = make(map[string][]byte)
+			}
+			secret.Data[inventoryDataKey] = data
+		},
+	}
+}
+
+func (s *objectStore) Load(ctx context.Context) (*Inventory, error) {
+	return s.load(ctx, s.client)
+}
+
+// load reads the inventory through reader, which may bypass the client's cache.
+func (s *objectStore) load(ctx context.Context, reader client.Reader) (*Inventory, error) {
+	if s.keyErr != nil {
+		return nil, s.keyErr
+	}
+	obj := s.newObject()
+	if err := reader.Get(ctx, s.key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &Inventory{}, nil
+		}
+		return nil, fmt.Errorf("failed to load inventory from %s: %w", s.key, err)
+	}
+	return decodeInventory(s.getData(obj))
+}
+
+func (s *objectStore) Save(ctx context.Context, inventory *Inventory) error {
+	if s.keyErr != nil {
+		return s.keyErr
+	}
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory: %w", err)
+	}
+
+	obj := s.newObject()
+	if err := s.client.Get(ctx, s.key, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get inventory %s: %w", s.key, err)
+		}
+
+		obj.SetName(s.key.Name)
+		obj.SetNamespace(s.key.Namespace)
+		if s.key.Namespace == s.owner.GetNamespace() {
+			if err := controllerutil.SetOwnerReference(s.owner, obj, s.client.Scheme()); err != nil {
+				return fmt.Errorf("failed to set owner reference on inventory %s: %w", s.key, err)
+			}
+		}
+		s.setData(obj, data)
+		if err := s.client.Create(ctx, obj); err != nil {
+			return fmt.Errorf("failed to create inventory %s: %w", s.key, err)
+		}
+		return nil
+	}
+
+	s.setData(obj, data)
+	if err := s.client.Update(ctx, obj); err != nil {
+		return fmt.Errorf("failed to update inventory %s: %w", s.key, err)
+	}
+	return nil
+}
+
+// identity returns the identity of the ConfigMap or Secret itself, which is
+// never a child of the owner even though it carries an ownerReference to it.
+func (s *objectStore) identity() ChildIdentity {
+	return ChildIdentity{Kind: s.kind, Namespace: s.key.Namespace, Name: s.key.Name}
+}
+
+// inventoryObjectKey returns the key of the ConfigMap or Secret holding the
+// inventory of owner. The name includes the owner's kind, so owners of
+// different kinds sharing a name do not share an inventory.
+func inventoryObjectKey(c client.Client, owner client.Object, namespace string) (client.ObjectKey, error) {
+	if namespace == "" {
+		namespace = owner.GetNamespace()
+	}
+	gvk, err := apiutil.GVKForObject(owner, c.Scheme())
+	if err != nil {
+		return client.ObjectKey{}, fmt.Errorf("failed to resolve the owner kind for the inventory name: %w", err)
+	}
+	name := owner.GetName() + "-" + strings.ToLower(gvk.Kind) + "-inventory"
+	return client.ObjectKey{Namespace: namespace, Name: name}, nil
+}
+
+// decodeInventory parses a JSON encoded inventory; empty data is an empty inventory.
+func decodeInventory(data []byte) (*Inventory, error) {
+	inventory := &Inventory{}
+	if len(data) == 0 {
+		return inventory, nil
+	}
+	if err := json.Unmarshal(data, inventory); err != nil {
+		return nil, fmt.Errorf("failed to decode inventory: %w", err)
+	}
+	return inventory, nil
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAnnotationStore(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	pruner, err := NewPrunerWithStore(ctx, cl, owner, NewAnnotationStore(cl, owner, ""), WithScheme(scheme))
+	if err != nil {
+		t.Fatalf("NewPrunerWithStore failed: %v", err)
+	}
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if err := pruner.Save(ctx); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	stored := &TestCR{}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(owner), stored); err != nil {
+		t.Fatalf("Failed to get owner: %v", err)
+	}
+	inventory, err := NewAnnotationStore(cl, stored, "").Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(inventory.Children) != 1 || inventory.CompletedGeneration != 1 {
+		t.Errorf("Expected 1 child completed at generation 1, got %+v", inventory)
+	}
+}
+
+func TestConfigMapStore(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+	deployment := newTestDeployment("test-deployment")
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	store := NewConfigMapStore(cl, owner, "")
+	pruner, err := NewPrunerWithStore(ctx, cl, owner, store, WithScheme(scheme))
+	if err != nil {
+		t.Fatalf("NewPrunerWithStore failed: %v", err)
+	}
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if err := pruner.Save(ctx); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test-owner-testcr-inventory"}, cm); err != nil {
+		t.Fatalf("Expected inventory ConfigMap to be created: %v", err)
+	}
+	if len(cm.OwnerReferences) != 1 || cm.OwnerReferences[0].UID != owner.UID {
+		t.Errorf("Expected inventory ConfigMap to be owned by the owner, got %+v", cm.OwnerReferences)
+	}
+
+	// Generation 2 loads the inventory from the ConfigMap and prunes
+	owner.SetGeneration(2)
+	pruner2, err := NewPrunerWithStore(ctx, cl, owner, store, WithScheme(scheme))
+	if err != nil {
+		t.Fatalf("NewPrunerWithStore failed: %v", err)
+	}
+	result, err := pruner2.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if err := pruner2.Save(ctx); err != nil {
+		t.Fatalf("Second Save failed: %v", err)
+	}
+
+	if len(result.Pruned()) != 1 {
+		t.Errorf("Expected 1 pruned resource, got %d", len(result.Pruned()))
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}); err == nil {
+		t.Errorf("Expected deployment to be deleted")
+	}
+	inventory, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(inventory.Children) != 0 || inventory.CompletedGeneration != 2 {
+		t.Errorf("Expected an empty inventory completed at generation 2, got %+v", inventory)
+	}
+}
+
+func TestSecretStore(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	store := NewSecretStore(cl, owner, "inventories")
+
+	inventory, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load of a missing Secret failed: %v", err)
+	}
+	if len(inventory.Children) != 0 {
+		t.Fatalf("Expected an empty inventory, got %+v", inventory)
+	}
+
+	inventory.CompletedGeneration = 3
+	inventory.Children = ManagedChildrenList{{
+		ObjectReference:    corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Namespace: "default", Name: "web"},
+		ObservedGeneration: 3,
+	}}
+	if err := store.Save(ctx, inventory); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: "inventories", Name: "test-owner-testcr-inventory"}, secret); err != nil {
+		t.Fatalf("Expected inventory Secret to be created: %v", err)
+	}
+	if len(secret.OwnerReferences) != 0 {
+		t.Errorf("Expected no ownerReference across namespaces, got %+v", secret.OwnerReferences)
+	}
+
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Children) != 1 || loaded.CompletedGeneration != 3 {
+		t.Errorf("Expected the saved inventory back, got %+v", loaded)
+	}
+}
+
+func TestObjectStore_NameIncludesOwnerKind(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	// Owners of different kinds may share a name
+	crStore := NewConfigMapStore(cl, newTestOwner(1), "").(*objectStore)
+	cmStore := NewConfigMapStore(cl, newConfigMapOwner(), "").(*objectStore)
+
+	if crStore.key.Name != "test-owner-testcr-inventory" {
+		t.Errorf("Expected test-owner-testcr-inventory, got %s", crStore.key.Name)
+	}
+	if crStore.key == cmStore.key {
+		t.Errorf("Expected owners of different kinds to get different inventories, both got %s", crStore.key)
+	}
+}
+
+func TestConfigMapStore_StoreObjectIsNotAChild(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+	store := NewConfigMapStore(cl, owner, "")
+	pruner, err := NewPrunerWithStore(ctx, cl, owner, store, WithScheme(scheme))
+	if err != nil {
+		t.Fatalf("NewPrunerWithStore failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if err := pruner.Save(ctx); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	cmKey := client.ObjectKey{Namespace: "default", Name: "test-owner-testcr-inventory"}
+
+	// The stored ConfigMap has an ownerReference to the owner but is not recovered
+	owner.SetGeneration(2)
+	pruner, err = NewPrunerWithStore(ctx, cl, owner, store, WithScheme(scheme))
+	if err != nil {
+		t.Fatalf("NewPrunerWithStore failed: %v", err)
+	}
+	recovered, err := pruner.RecoverInventory(ctx, []schema.GroupVersionKind{
+		corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+	}, nil)
+	if err != nil {
+		t.Fatalf("RecoverInventory failed: %v", err)
+	}
+	if len(recovered) != 0 {
+		t.Errorf("Expected the inventory ConfigMap not to be recovered, got %+v", recovered)
+	}
+
+	// An inventory that tracks it anyway never deletes it
+	if err := store.Save(ctx, &Inventory{
+		CompletedGeneration: 1,
+		Children: ManagedChildrenList{{
+			ObjectReference:    corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: cmKey.Namespace, Name: cmKey.Name},
+			ObservedGeneration: 1,
+		}},
+	}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	pruner, err = NewPrunerWithStore(ctx, cl, owner, store, WithScheme(scheme))
+	if err != nil {
+		t.Fatalf("NewPrunerWithStore failed: %v", err)
+	}
+	result, err := pruner.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if got := result.Children[0]; got.Outcome != OutcomeSkipped || got.Reason != SkipReasonInventoryObject {
+		t.Errorf("Expected the inventory ConfigMap to be skipped, got %+v", got)
+	}
+	if err := cl.Get(ctx, cmKey, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("Expected the inventory ConfigMap to still exist: %v", err)
+	}
+	if err := pruner.Save(ctx); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	inventory, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(inventory.Children) != 0 {
+		t.Errorf("Expected the inventory ConfigMap to be dropped from the inventory, got %+v", inventory.Children)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// PruneAll deletes every child recorded in the inventory, regardless of
+// generation or of what was marked in this session. It is meant for owner
+// deletion, where cross-namespace and cluster-scoped children cannot rely on
+// ownerReference garbage collection.
+//
+// Children are only removed from the inventory once they are confirmed gone.
+// Children that still exist after the delete call (typically because they have
+// finalizers) stay in the inventory in the Deleting state and are returned as
+// still terminating.
+// Deletion follows the configured DeletionOrder: later waves are left in the
+// inventory until every child of the earlier waves is gone.
+//
+// Example:
+//
+//	terminating, err := pruner.PruneAll(ctx)
+//	if err != nil {
+//	    return ctrl.Result{}, err
+//	}
+//	if len(terminating) > 0 {
+//	    return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+//	}
+func (p *Pruner) PruneAll(ctx context.Context) ([]corev1.ObjectReference, error) {
+	p.seal()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.pruneAll(ctx)
+}
+
+// pruneAll implements PruneAll; the caller must have sealed the session and
+// must hold p.mu.
+func (p *Pruner) pruneAll(ctx context.Context) ([]corev1.ObjectReference, error) {
+	if err := p.checkFreshOwner(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		pruneErrors []error
+		terminating []corev1.ObjectReference
+	)
+	removed := make(map[ChildIdentity]struct{})
+	deleting := make(map[ChildIdentity]ChildState)
+
+	for _, wave := range p.deletionOrder.waves(*p.statusChildren) {
+		// Later waves wait until every child of this one is gone
+		if len(pruneErrors) > 0 || len(terminating) > 0 {
+			break
+		}
+
+		for i, result := range p.removeWave(ctx, wave, true, ReasonTeardown) {
+			child := wave[i]
+			switch result.Outcome {
+			case OutcomeFailed:
+				pruneErrors = append(pruneErrors, result.Error)
+			case OutcomeDeleting:
+				terminating = append(terminating, child.ObjectReference)
+				deleting[child.Identity()] = ChildStateDeleting
+			default:
+				removed[child.Identity()] = struct{}{}
+			}
+		}
+	}
+
+	*p.statusChildren = updateChildren(*p.statusChildren, removed, deleting)
+	p.recordInventorySize()
+	return terminating, errors.Join(pruneErrors...)
+}
+
+// EnsureFinalizer adds the finalizer to the owner if it is missing.
+// Call it at the beginning of a reconcile, before marking any child, so that
+// the owner cannot disappear before Teardown has emptied the inventory.
+func (p *Pruner) EnsureFinalizer(ctx context.Context, finalizer string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	before := p.owner.DeepCopyObject().(client.Object)
+	if !controllerutil.AddFinalizer(p.owner, finalizer) {
+		return nil
+	}
+	if err := p.client.Patch(ctx, p.owner, client.MergeFrom(before)); err != nil {
+		return fmt.Errorf("failed to add finalizer %q: %w", finalizer, err)
+	}
+	return nil
+}
+
+// Teardown deletes every child in the inventory using PruneAll and removes the
+// finalizer from the owner once the inventory is empty.
+// Call it when the owner has a deletion timestamp.
+//
+// It returns the children that are still terminating. While that list is not
+// empty the finalizer is kept: persist the owner's status and requeue. In
+// dry-run mode the finalizer is never removed.
+//
+// Example:
+//
+//	if !myCR.GetDeletionTimestamp().IsZero() {
+//	    terminating, err := pruner.Teardown(ctx, "example.com/children")
+//	    if err != nil || len(terminating) > 0 {
+//	        _ = r.Status().Update(ctx, &myCR)
+//	        return ctrl.Result{RequeueAfter: 5 * time.Second}, err
+//	    }
+//	    return ctrl.Result{}, nil
+//	}
+func (p *Pruner) Teardown(ctx context.Context, finalizer string) ([]corev1.ObjectReference, error) {
+	p.seal()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	terminating, err := p.pruneAll(ctx)
+	if err != nil || len(*p.statusChildren) > 0 || p.dryRun {
+		return terminating, err
+	}
+
+	before := p.owner.DeepCopyObject().(client.Object)
+	if !controllerutil.RemoveFinalizer(p.owner, finalizer) {
+		return nil, nil
+	}
+	if err := p.client.Patch(ctx, p.owner, client.MergeFrom(before)); err != nil {
+		return nil, fmt.Errorf("failed to remove finalizer %q: %w", finalizer, err)
+	}
+	return nil, nil
+}
+
+// isGone reports whether the child no longer exists in the cluster.
+// A child re-created with another UID is considered gone.
+func (p *Pruner) isGone(ctx context.Context, child ManagedChild) (bool, error) {
+	live := childObject(child)
+	if err := p.client.Get(ctx, client.ObjectKeyFromObject(live), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if uid := child.ObjectReference.UID; uid != "" && live.GetUID() != uid {
+		return true, nil
+	}
+	return false, nil
+}
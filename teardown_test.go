@@ -0,0 +1,139 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const testFinalizer = "example.com/children"
+
+func TestPruner_PruneAll(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	deployment := newTestDeployment("test-deployment")
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", UID: "test-namespace-uid"},
+	}
+	for _, obj := range []client.Object{deployment, namespace} {
+		if err := cl.Create(ctx, obj); err != nil {
+			t.Fatalf("Failed to create child: %v", err)
+		}
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	for _, obj := range []client.Object{deployment, namespace} {
+		if err := pruner.MarkReconciled(obj); err != nil {
+			t.Fatalf("MarkReconciled failed: %v", err)
+		}
+	}
+
+	// Marked children are deleted too: the owner is going away
+	terminating, err := pruner.PruneAll(ctx)
+	if err != nil {
+		t.Fatalf("PruneAll failed: %v", err)
+	}
+
+	if len(terminating) != 0 {
+		t.Errorf("Expected no terminating children, got %+v", terminating)
+	}
+	if len(owner.Status.Inventory.Children) != 0 {
+		t.Errorf("Expected empty inventory, got %d children", len(owner.Status.Inventory.Children))
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(namespace), &corev1.Namespace{}); err == nil {
+		t.Errorf("Expected cluster-scoped child to be deleted")
+	}
+}
+
+func TestPruner_TeardownWaitsForTerminatingChildren(t *testing.T) {
+	ctx := context.Background()
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	owner := newTestOwner(1)
+	if err := cl.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create owner: %v", err)
+	}
+
+	deployment := newTestDeployment("test-deployment")
+	deployment.Finalizers = []string{"example.com/slow"}
+	if err := cl.Create(ctx, deployment); err != nil {
+		t.Fatalf("Failed to create deployment: %v", err)
+	}
+
+	pruner := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	if err := pruner.EnsureFinalizer(ctx, testFinalizer); err != nil {
+		t.Fatalf("EnsureFinalizer failed: %v", err)
+	}
+	if err := pruner.MarkReconciled(deployment); err != nil {
+		t.Fatalf("MarkReconciled failed: %v", err)
+	}
+	if _, err := pruner.Prune(ctx); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	// The owner is deleted; its finalizer keeps it around
+	inventory := owner.Status.Inventory.DeepCopy()
+	if err := cl.Delete(ctx, owner); err != nil {
+		t.Fatalf("Failed to delete owner: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(owner), owner); err != nil {
+		t.Fatalf("Failed to get owner: %v", err)
+	}
+	owner.Status.Inventory = *inventory
+
+	pruner2 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	terminating, err := pruner2.Teardown(ctx, testFinalizer)
+	if err != nil {
+		t.Fatalf("Teardown failed: %v", err)
+	}
+	if len(terminating) != 1 {
+		t.Fatalf("Expected 1 terminating child, got %+v", terminating)
+	}
+	if !controllerutil.ContainsFinalizer(owner, testFinalizer) {
+		t.Fatalf("Finalizer must be kept while children are terminating")
+	}
+
+	// The child's own finalizer completes
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatalf("Failed to get deployment: %v", err)
+	}
+	deployment.Finalizers = nil
+	if err := cl.Update(ctx, deployment); err != nil {
+		t.Fatalf("Failed to remove deployment finalizer: %v", err)
+	}
+
+	pruner3 := NewInventoryPruner(cl, owner, &owner.Status.Inventory, WithScheme(scheme))
+	terminating, err = pruner3.Teardown(ctx, testFinalizer)
+	if err != nil {
+		t.Fatalf("Second Teardown failed: %v", err)
+	}
+	if len(terminating) != 0 {
+		t.Errorf("Expected no terminating children, got %+v", terminating)
+	}
+	if controllerutil.ContainsFinalizer(owner, testFinalizer) {
+		t.Errorf("Expected finalizer to be removed once the inventory is empty")
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// tracerName is the instrumentation scope of the pruner spans.
+const tracerName = "github.com/guilhem/reconcileprune"
+
+// tracer returns the tracer from the provider set with WithTracerProvider,
+// or from the global provider.
+func (p *Pruner) tracer() trace.Tracer {
+	tp := p.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
+
+// startPruneSpan opens the span wrapping a Prune call.
+func (p *Pruner) startPruneSpan(ctx context.Context) (context.Context, trace.Span) {
+	return p.tracer().Start(ctx, "reconcileprune.Prune", trace.WithAttributes(
+		attribute.String("reconcileprune.owner.kind", p.ownerGroupKind().String()),
+		attribute.String("reconcileprune.owner.namespace", p.owner.GetNamespace()),
+		attribute.String("reconcileprune.owner.name", p.owner.GetName()),
+		attribute.Int64("reconcileprune.generation", p.generation),
+		attribute.Int64("reconcileprune.last_applied_generation", p.lastAppliedGen),
+		attribute.Int("reconcileprune.inventory_size", len(*p.statusChildren)),
+	))
+}
+
+// endPruneSpan records the outcome of a Prune call and ends its span.
+func endPruneSpan(span trace.Span, result *PruneResult, err error) {
+	for outcome, count := range result.Counts {
+		span.SetAttributes(attribute.Int("reconcileprune.children."+strings.ToLower(string(outcome)), count))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startDeleteSpan opens the span wrapping the API calls removing a child.
+func (p *Pruner) startDeleteSpan(ctx context.Context, ref corev1.ObjectReference) (context.Context, trace.Span) {
+	return p.tracer().Start(ctx, "reconcileprune.Delete", trace.WithAttributes(
+		attribute.String("reconcileprune.child.gvk", ref.GroupVersionKind().String()),
+		attribute.String("reconcileprune.child.namespace", ref.Namespace),
+		attribute.String("reconcileprune.child.name", ref.Name),
+		attribute.Bool("reconcileprune.dry_run", p.dryRun),
+	))
+}
+
+// endDeleteSpan records the outcome of the API calls removing a child and
+// ends its span.
+func endDeleteSpan(span trace.Span, attempt removalAttempt) {
+	span.SetAttributes(
+		attribute.Bool("reconcileprune.child.already_gone", attempt.alreadyGone),
+		attribute.Bool("reconcileprune.child.terminating", attempt.terminating),
+	)
+	if attempt.skipReason != "" {
+		span.SetAttributes(attribute.String("reconcileprune.child.skip_reason", attempt.skipReason))
+	}
+	if attempt.err != nil {
+		span.RecordError(attempt.err)
+		span.SetStatus(codes.Error, attempt.err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 The Kubernetes Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcileprune
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// spanAttribute returns the value of an attribute of a recorded span.
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing_PruneSpans(t *testing.T) {
+	scheme := setupScheme()
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				if obj.GetName() == "failed" {
+					return errors.New("delete failed")
+				}
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	_, err := pruneAfterRemoval(t, cl, newTestOwner(1),
+		[]client.Object{newTestDeployment("deleted"), newTestDeployment("failed")},
+		WithScheme(scheme),
+		WithTracerProvider(tp),
+	)
+	if err == nil {
+		t.Fatal("Expected Prune to fail")
+	}
+
+	var pruneSpans, deleteSpans []tracetest.SpanStub
+	for _, span := range exporter.GetSpans() {
+		switch span.Name {
+		case "reconcileprune.Prune":
+			pruneSpans = append(pruneSpans, span)
+		case "reconcileprune.Delete":
+			deleteSpans = append(deleteSpans, span)
+		}
+	}
+
+	// One span for each of the two Prune calls, the last one pruning generation 2
+	if len(pruneSpans) != 2 {
+		t.Fatalf("Expected 2 Prune spans, got %d", len(pruneSpans))
+	}
+	prune := pruneSpans[1]
+	if got := spanAttribute(prune, "reconcileprune.owner.name").AsString(); got != "test-owner" {
+		t.Errorf("Expected the owner name attribute, got %q", got)
+	}
+	if got := spanAttribute(prune, "reconcileprune.generation").AsInt64(); got != 2 {
+		t.Errorf("Expected generation 2, got %d", got)
+	}
+	if got := spanAttribute(prune, "reconcileprune.last_applied_generation").AsInt64(); got != 1 {
+		t.Errorf("Expected last applied generation 1, got %d", got)
+	}
+	if spanAttribute(prune, "reconcileprune.children.deleted").AsInt64() != 1 ||
+		spanAttribute(prune, "reconcileprune.children.failed").AsInt64() != 1 {
+		t.Errorf("Expected outcome counts on the Prune span, got %v", prune.Attributes)
+	}
+	if prune.Status.Code != codes.Error {
+		t.Errorf("Expected the Prune span to be marked as failed, got %v", prune.Status)
+	}
+
+	if len(deleteSpans) != 2 {
+		t.Fatalf("Expected 2 Delete spans, got %d", len(deleteSpans))
+	}
+	for _, span := range deleteSpans {
+		if span.Parent.SpanID() != prune.SpanContext.SpanID() {
+			t.Errorf("Expected Delete span %v to be a child of the Prune span", span.Attributes)
+		}
+		name := spanAttribute(span, "reconcileprune.child.name").AsString()
+		if failed := span.Status.Code == codes.Error; failed != (name == "failed") {
+			t.Errorf("Unexpected status %v for the Delete span of %s", span.Status, name)
+		}
+	}
+}
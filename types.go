@@ -18,9 +18,45 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ChildIdentity is the canonical identity of a managed child resource.
+// It deliberately leaves out the API version, UID and ResourceVersion so that
+// updates, version migrations and re-creations of the same object all map to
+// a single inventory entry.
+type ChildIdentity struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// IdentityFromReference returns the canonical identity of the referenced object.
+func IdentityFromReference(ref corev1.ObjectReference) ChildIdentity {
+	gv, _ := schema.ParseGroupVersion(ref.APIVersion)
+	return ChildIdentity{
+		Group:     gv.Group,
+		Kind:      ref.Kind,
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
+	}
+}
+
+// GroupKind returns the group and kind part of the identity.
+func (id ChildIdentity) GroupKind() schema.GroupKind {
+	return schema.GroupKind{Group: id.Group, Kind: id.Kind}
+}
+
+// String returns a human readable form such as "Deployment.apps default/web".
+func (id ChildIdentity) String() string {
+	if id.Namespace == "" {
+		return id.GroupKind().String() + " " + id.Name
+	}
+	return id.GroupKind().String() + " " + id.Namespace + "/" + id.Name
+}
+
 // ManagedChild represents a single managed child resource.
 type ManagedChild struct {
 	// ObjectReference identifies the child resource.
@@ -29,12 +65,111 @@ type ManagedChild struct {
 	// ObservedGeneration is the parent's metadata.generation when this child was last applied.
 	// Used to determine which resources should be pruned on the next reconciliation.
 	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// State is the lifecycle state of the child. It is empty for applied children.
+	State ChildState `json:"state,omitempty"`
+}
+
+// ChildState is the lifecycle state of a managed child.
+type ChildState string
+
+const (
+	// ChildStateDeleting marks a child whose deletion was accepted by the API
+	// server but which still exists, typically because of finalizers.
+	ChildStateDeleting ChildState = "Deleting"
+
+	// ChildStatePending marks a child recorded by RecordIntent whose apply has
+	// not been confirmed by MarkReconciled yet.
+	ChildStatePending ChildState = "Pending"
+
+	// ChildStatePendingPrune marks a stale child whose deletion wave was held
+	// back by an incomplete earlier wave. Later reconciles prune it even if
+	// the owner's generation does not change, unless it is marked again.
+	ChildStatePendingPrune ChildState = "PendingPrune"
+)
+
+// Identity returns the canonical identity of the child.
+func (c ManagedChild) Identity() ChildIdentity {
+	return IdentityFromReference(c.ObjectReference)
 }
 
 // ManagedChildrenList is a list of managed child resources.
 // This type alias is used to clarify the purpose of the children slice in the Pruner.
 type ManagedChildrenList []ManagedChild
 
+// Index returns the position of the child with the given identity, or -1 if
+// the list does not contain it.
+func (l ManagedChildrenList) Index(id ChildIdentity) int {
+	for i := range l {
+		if l[i].Identity() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Inventory is the persisted state of a Pruner: the managed children plus a
+// commit marker recording the last generation whose Prune completed.
+// Embed it in the owner's status and use NewInventoryPruner.
+type Inventory struct {
+	// Children lists all managed child resources.
+	Children ManagedChildrenList `json:"children,omitempty"`
+
+	// CompletedGeneration is the owner's metadata.generation for which Prune last
+	// completed successfully. It is only advanced once every stale child has been
+	// handled, so a generation whose reconcile failed half-way is pruned again.
+	CompletedGeneration int64 `json:"completedGeneration,omitempty"`
+
+	// Fingerprint is the fingerprint of the inputs for which Prune last
+	// completed successfully. It is only set with PruneOnFingerprintChange,
+	// EpochFromSpecHash or EpochFromResourceVersion.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *Inventory) DeepCopyInto(out *Inventory) {
+	*out = *in
+	if in.Children != nil {
+		out.Children = make(ManagedChildrenList, len(in.Children))
+		copy(out.Children, in.Children)
+	}
+}
+
+// DeepCopy returns a deep copy of the inventory.
+func (in *Inventory) DeepCopy() *Inventory {
+	if in == nil {
+		return nil
+	}
+	out := new(Inventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// SkipReasonIdentityMismatch is reported when the live object no longer matches
+// the recorded child, for example because it was re-created by another tool or
+// lost the owner's ownerReference or tracking label.
+const SkipReasonIdentityMismatch = "identity mismatch"
+
+// SkipReasonProtected is reported for children whose kind is protected by a PruneRule.
+const SkipReasonProtected = "protected"
+
+// SkipReasonAnnotation is reported for children whose live object opts out of
+// pruning through an annotation.
+const SkipReasonAnnotation = "prune disabled by annotation"
+
+// SkipReasonInventoryObject is reported for the ConfigMap or Secret holding the
+// Pruner's own inventory, which is never deleted as a child.
+const SkipReasonInventoryObject = "inventory object"
+
+// SkippedChild is a child that Prune decided not to delete.
+type SkippedChild struct {
+	// ObjectReference identifies the child resource.
+	ObjectReference corev1.ObjectReference
+
+	// Reason explains why the child was not deleted.
+	Reason string
+}
+
 // ErrorHandlerFunc is called when an error occurs during pruning operations.
 // It receives the context, the error, and the object being processed.
 // Return nil to ignore the error, or return/wrap the error to fail the operation.